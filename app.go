@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"github.com/mattn/go-gtk/gdk"
 	"github.com/mattn/go-gtk/glib"
 	"github.com/mattn/go-gtk/gtk"
@@ -9,7 +10,6 @@ import (
 	"log"
 	"math"
 	"strconv"
-	"time"
 )
 
 // Global drawing area pieces
@@ -19,20 +19,197 @@ var blackgc *gdk.GC
 var redgc *gdk.GC
 var bluegc *gdk.GC
 
+// Label reporting the simulated time and diagnostics
+var statuslabel *gtk.Label
+
+// Diagnostics of the entities when last reset, softened like the current solver and summed with compensation
+// when it is, for the status label to show drift against
+var reference physics.Diagnostics
+var softening physics.Softening
+var compensated bool
+
+// Units the entity table, the status label and the simulation's gravitational constant are in
+var units = unitsystems[0]
+
+// Simulation of entities free to move in three dimensions when reset in 3D, or nil, the energy it had when reset
+// and the camera it is viewed through
+var simulation3d *physics.Simulation3D
+var reference3d float64
+var camera physics.Camera
+
 // Size of the drawable area
 const width int = 640
 const height int = 640
 
 // Entity limits
-const entityfields int = 7
+const entityfields int = 10
 const entitylimit int = 18
 
-// How much to damp velocity on colliding with the outside walls
+// How much to damp velocity on colliding with the outside walls by default
 const damping float64 = 0.7
 
-// Parse the entities from user input and return a slice of valid entities
-func initentities(entries [][]*gtk.Entry) []*physics.Entity {
-	entities := make([]*physics.Entity, 0)
+// The drawing area as a region of the simulation plane, in the current units
+var area = physics.NewDomain(float64(width), float64(height))
+
+// Simulated playground seconds advanced by each tick
+const tick float64 = 0.01
+
+// Specific impact energy in playground units above which colliding entities shatter
+const shatterenergy float64 = 100
+
+// Integration schemes selectable on the simulation tab, the first is the default
+var integrators = []struct {
+	name       string
+	integrator physics.Integrator
+}{
+	{"Velocity Verlet", physics.VelocityVerlet{}},
+	{"Leapfrog", physics.Leapfrog{}},
+	{"Runge-Kutta 4", physics.RK4{}},
+	{"Forest-Ruth", physics.ForestRuth{}},
+	{"Yoshida 4th order", physics.Yoshida4{}},
+	{"Yoshida 6th order", physics.Yoshida6{}},
+	{"Semi-implicit Euler", physics.SemiImplicitEuler{}},
+	{"Explicit Euler", physics.Euler{}},
+	{"Dormand-Prince 5(4)", physics.NewDormandPrince(1e-6, 1e-6)},
+	{"Hermite", physics.NewHermite(0.01)},
+	{"Block timestep Hermite", physics.NewBlockHermite(0.01, 1.0/64, 20)},
+}
+
+// Set the largest block step of the block timestep Hermite integrator to the smallest power of two no shorter than a
+// tick in the current units. Every tick starts the blocks over, so a shorter largest step would make even the
+// slowest entities take several steps per tick
+func updateblocksteps() {
+	for _, choice := range integrators {
+		if integrator, ok := choice.integrator.(*physics.BlockHermite); ok {
+			integrator.MaxStep = math.Exp2(math.Ceil(math.Log2(inunits(tick, physics.Dimensions.Time))))
+		}
+	}
+}
+
+// Softening kernels selectable on the simulation tab, the first is the default
+var kernels = []physics.Kernel{physics.NoSoftening, physics.Plummer, physics.Spline}
+
+// Watchdog policies selectable on the simulation tab, the first is the default
+var policies = []physics.Policy{physics.LogAlarm, physics.PauseOnAlarm, physics.HaltOnAlarm}
+
+// Systems of units selectable on the simulation tab, the first is the default
+var unitsystems = []physics.Units{physics.Playground, physics.SI, physics.Astronomical}
+
+// Relative energy drift the watchdog allows by default
+const maxdrift float64 = 0.1
+
+// Settings from the simulation tab used to build the force solver
+type solversettings struct {
+	softening   physics.Softening
+	theta       float64
+	order       int
+	periodic    bool
+	compensated bool
+}
+
+// Force solvers selectable on the simulation tab, the first is the default
+var solvers = []struct {
+	name  string
+	build func(settings solversettings) physics.Solver
+}{
+	{"Direct summation", func(settings solversettings) physics.Solver {
+		if settings.periodic {
+			return physics.MinimumImageSolver{Domain: area, Softening: settings.softening}
+		}
+		return physics.ParallelSolver{Softening: settings.softening, Compensated: settings.compensated}
+	}},
+	{"Pairwise summation", func(settings solversettings) physics.Solver {
+		if settings.periodic {
+			return physics.MinimumImageSolver{Domain: area, Softening: settings.softening}
+		}
+		return physics.PairwiseSolver{Softening: settings.softening}
+	}},
+	{"Barnes-Hut", func(settings solversettings) physics.Solver {
+		if settings.periodic {
+			return physics.MinimumImageSolver{Domain: area, Softening: settings.softening}
+		}
+		return physics.BarnesHutSolver{Theta: settings.theta, Softening: settings.softening}
+	}},
+	{"Fast multipole", func(settings solversettings) physics.Solver {
+		if settings.periodic {
+			return physics.MinimumImageSolver{Domain: area, Softening: settings.softening}
+		}
+		return physics.FMMSolver{Order: settings.order, Softening: settings.softening}
+	}},
+	{"Particle mesh", func(settings solversettings) physics.Solver {
+		return physics.PMSolver{Grid: 128, Domain: area}
+	}},
+}
+
+// Boundaries of the drawing area selectable on the simulation tab, the first is the default
+var boundaries = []struct {
+	name  string
+	build func(restitution float64, friction float64) physics.Boundary
+}{
+	{"Reflective walls", func(restitution float64, friction float64) physics.Boundary {
+		return physics.ReflectiveBoundary{Domain: area, Restitution: restitution, Friction: friction}
+	}},
+	{"Periodic", func(restitution float64, friction float64) physics.Boundary {
+		return physics.PeriodicBoundary{Domain: area}
+	}},
+	{"Absorbing", func(restitution float64, friction float64) physics.Boundary {
+		return physics.AbsorbingBoundary{Domain: area}
+	}},
+	{"Open", func(restitution float64, friction float64) physics.Boundary { return physics.OpenBoundary{} }},
+}
+
+// Views of three dimensional simulations selectable on the simulation tab, the first is the default
+var views = []struct {
+	name   string
+	camera physics.Camera
+}{
+	{"XY plane", physics.ViewXY},
+	{"XZ plane", physics.ViewXZ},
+	{"YZ plane", physics.ViewYZ},
+}
+
+// Collision modes selectable on the simulation tab, the first is the default. Entities touch across the edges of
+// the periodic domain, which is the zero Domain for other boundaries
+var colliders = []struct {
+	name  string
+	build func(restitution float64, periodic physics.Domain) physics.Collider
+}{
+	{"Pass through", func(restitution float64, periodic physics.Domain) physics.Collider { return nil }},
+	{"Merge", func(restitution float64, periodic physics.Domain) physics.Collider {
+		return &physics.Merger{Domain: periodic}
+	}},
+	{"Bounce", func(restitution float64, periodic physics.Domain) physics.Collider {
+		return &physics.Bouncer{Restitution: restitution, Domain: periodic}
+	}},
+	{"Shatter", func(restitution float64, periodic physics.Domain) physics.Collider {
+		return &physics.Fragmenter{Threshold: inunits(shatterenergy, physics.Dimensions.SpecificEnergy), Fragments: 4, MaxFragments: 16, Dispersion: 0.5, Domain: periodic}
+	}},
+}
+
+// Names, column titles and dimensions of the entity fields in the order of the entity table columns, with the
+// trailing z fields optional so planar entities can leave them blank
+var fields = []struct {
+	name      string
+	title     string
+	dimension physics.Dimension
+}{
+	{"mass", "Mass", physics.Dimensions.Mass},
+	{"x position", "X-Pos", physics.Dimensions.Length},
+	{"y position", "Y-Pos", physics.Dimensions.Length},
+	{"x velocity", "X-Vel", physics.Dimensions.Velocity},
+	{"y velocity", "Y-Vel", physics.Dimensions.Velocity},
+	{"x acceleration", "X-Acc", physics.Dimensions.Acceleration},
+	{"y acceleration", "Y-Acc", physics.Dimensions.Acceleration},
+	{"z position", "Z-Pos", physics.Dimensions.Length},
+	{"z velocity", "Z-Vel", physics.Dimensions.Velocity},
+	{"z acceleration", "Z-Acc", physics.Dimensions.Acceleration},
+}
+
+const planarfields int = 7
+
+// Parse the fields of every non-empty row of user input, skipping rows with a field that does not parse
+func parserows(entries [][]*gtk.Entry) [][]float64 {
+	rows := make([][]float64, 0)
 
 	for rownum, row := range entries {
 		// Check if row is empty, skip if true
@@ -46,98 +223,149 @@ func initentities(entries [][]*gtk.Entry) []*physics.Entity {
 			continue
 		}
 
-		// Parse mass
-		mass, err := strconv.ParseFloat(row[0].GetText(), 64)
-		if err != nil {
-			log.Printf("Could not parse mass in row %v: %v - skipping", rownum, err)
-			continue
-		}
-
-		// Parse x position
-		posx, err := strconv.ParseFloat(row[1].GetText(), 64)
-		if err != nil {
-			log.Printf("Could not parse x position in row %v: %v - skipping", rownum, err)
-			continue
-		}
-
-		// Parse y position
-		posy, err := strconv.ParseFloat(row[2].GetText(), 64)
-		if err != nil {
-			log.Printf("Could not parse y position in row %v: %v - skipping", rownum, err)
-			continue
-		}
-
-		// Parse x velocity
-		velx, err := strconv.ParseFloat(row[3].GetText(), 64)
-		if err != nil {
-			log.Printf("Could not parse x velocity in row %v: %v - skipping", rownum, err)
-			continue
-		}
-
-		// Parse y velocity
-		vely, err := strconv.ParseFloat(row[4].GetText(), 64)
-		if err != nil {
-			log.Printf("Could not parse y velocity in row %v: %v - skipping", rownum, err)
-			continue
+		// Parse every field, leaving blank optional fields at 0
+		values := make([]float64, len(fields))
+		valid := true
+		for col, field := range fields {
+			text := row[col].GetText()
+			if col >= planarfields && text == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				log.Printf("Could not parse %v in row %v: %v - skipping", field.name, rownum, err)
+				valid = false
+				break
+			}
+			values[col] = value
 		}
 
-		// Parse x acceleration
-		accelx, err := strconv.ParseFloat(row[5].GetText(), 64)
-		if err != nil {
-			log.Printf("Could not parse x acceleration in row %v: %v - skipping", rownum, err)
-			continue
+		// If all entity fields parsed, keep the row
+		if valid {
+			rows = append(rows, values)
 		}
+	}
 
-		// Parse y acceleration
-		accely, err := strconv.ParseFloat(row[6].GetText(), 64)
-		if err != nil {
-			log.Printf("Could not parse y acceleration in row %v: %v - skipping", rownum, err)
-			continue
-		}
+	return rows
+}
 
-		// If all entity fields parsed, append the new entity
-		entities = append(entities, physics.NewEntity(mass, posx, posy, velx, vely, accelx, accely))
+// Parse the entities from user input and return a slice of valid entities, ignoring the z fields
+func initentities(entries [][]*gtk.Entry) []*physics.Entity {
+	entities := make([]*physics.Entity, 0)
+	for _, v := range parserows(entries) {
+		entity := physics.NewEntity(v[0], v[1], v[2], v[3], v[4], v[5], v[6])
+		entity.Radius = radius(v[0])
+		entities = append(entities, entity)
 	}
-
 	return entities
 }
 
-// Update all entities acceleration then velocity and position and kick off a draw
-func updateentities(entities []*physics.Entity) {
-	// Update all graviational accelerations
-	for _, e := range entities {
-		e.UpdateGravitationalAcceleration(entities)
+// Parse the entities from user input and return a slice of valid entities free to move in three dimensions
+func initentities3d(entries [][]*gtk.Entry) []*physics.Entity3D {
+	entities := make([]*physics.Entity3D, 0)
+	for _, v := range parserows(entries) {
+		entity := physics.NewEntity3D(v[0], v[1], v[2], v[7], v[3], v[4], v[8], v[5], v[6], v[9])
+		entity.Radius = radius(v[0])
+		entities = append(entities, entity)
 	}
+	return entities
+}
+
+// Convert a value of the given dimension from playground units, which the drawing area, the tick and the other
+// controls are measured in, into the current units
+func inunits(value float64, dimension physics.Dimension) float64 {
+	return physics.Playground.Convert(value, dimension, units)
+}
 
-	// Once all gravitational accelerations are set move positions and velocities
-	for _, e := range entities {
-		bound(e)
-		e.Update(0.01)
+// Convert a value of the given dimension from the current units into playground units, where lengths are pixels
+func inplayground(value float64, dimension physics.Dimension) float64 {
+	return units.Convert(value, dimension, physics.Playground)
+}
+
+// Return the radius in the current units of an entity of the given mass, which is drawn with half the square root
+// of its mass in playground units as its radius in pixels
+func radius(mass float64) float64 {
+	return inunits(math.Sqrt(inplayground(mass, physics.Dimensions.Mass))/2, physics.Dimensions.Length)
+}
+
+// Advance the simulation by a tick with its current integrator and kick off a draw, returning the watchdog's alarm
+// if it stopped the simulation
+func updateentities(simulation *physics.Simulation) error {
+	var err error
+	if simulation3d != nil {
+		simulation3d.AdvanceTo(simulation3d.Time + inunits(tick, physics.Dimensions.Time))
+	} else {
+		err = simulation.AdvanceTo(simulation.Time + inunits(tick, physics.Dimensions.Time))
 	}
 
+	statuslabel.SetText(status(simulation))
 	drawingarea.QueueDraw()
+	return err
 }
 
-// Check that the entity does not leave the drawing area,
-func bound(e *physics.Entity) {
-	halfwidth := float64(width / 2)
-	halfheight := float64(height / 2)
-	if e.Position.X < -halfwidth && e.Velocity.X < 0 {
-		e.Velocity = e.Velocity.InvertX()
-		e.Velocity = e.Velocity.Scalarmul(damping)
+// Describe the simulated time, the steps taken and contacts resolved by adaptive integrators and colliders, and
+// the conservation diagnostics, or the simulated time, view and energy of a three dimensional simulation
+func status(simulation *physics.Simulation) string {
+	if simulation3d != nil {
+		energy := simulation3d.Energy()
+		return fmt.Sprintf("Time: %.4g %v - 3D, Yaw: %.0f°, Pitch: %.0f°\nEnergy: %v (drift %.1e)", simulation3d.Time, units.Symbol(physics.Dimensions.Time),
+			camera.Yaw*180/math.Pi, camera.Pitch*180/math.Pi, units.Format(energy, physics.Dimensions.Energy), simulation3d.EnergyDrift(reference3d))
 	}
-	if e.Position.X > halfwidth && e.Velocity.X > 0 {
-		e.Velocity = e.Velocity.InvertX()
-		e.Velocity = e.Velocity.Scalarmul(damping)
+	text := fmt.Sprintf("Time: %.4g %v", simulation.Time, units.Symbol(physics.Dimensions.Time))
+	switch integrator := simulation.Integrator.(type) {
+	case *physics.DormandPrince:
+		text += fmt.Sprintf(" - Accepted steps: %v, Rejected steps: %v", integrator.Accepted, integrator.Rejected)
+	case *physics.Hermite:
+		text += fmt.Sprintf(" - Steps: %v", integrator.Steps)
+	case *physics.BlockHermite:
+		text += fmt.Sprintf(" - Blocks: %v, Entity steps: %v", integrator.Blocks, integrator.Steps)
 	}
-	if e.Position.Y < -halfheight && e.Velocity.Y < 0 {
-		e.Velocity = e.Velocity.InvertY()
-		e.Velocity = e.Velocity.Scalarmul(damping)
+	switch collider := simulation.Collider.(type) {
+	case *physics.Merger:
+		text += fmt.Sprintf(" - Merges: %v", len(collider.Records))
+	case *physics.Bouncer:
+		text += fmt.Sprintf(" - Bounces: %v", len(collider.Records))
+	case *physics.Fragmenter:
+		text += fmt.Sprintf(" - Impacts: %v", len(collider.Records))
+	}
+	diagnostics := diagnose(simulation.Entities)
+	drift := diagnostics.Drift(reference)
+	text += fmt.Sprintf("\nEnergy: %v (drift %.1e), Momentum drift: %.1e, Angular momentum drift: %.1e, Virial ratio: %.3g",
+		units.Format(diagnostics.Energy(), physics.Dimensions.Energy), drift.Energy, drift.Momentum, drift.AngularMomentum, diagnostics.VirialRatio())
+	if alarm := simulation.Watchdog.First; alarm != nil {
+		text += fmt.Sprintf("\nWatchdog: %v (%v bad steps)", alarm, simulation.Watchdog.Alarms)
+	}
+	return text
+}
+
+// Diagnose the entities in the current units with the softening of the current solver, summing with compensation
+// when it does
+func diagnose(entities []*physics.Entity) physics.Diagnostics {
+	if compensated {
+		return units.DiagnoseCompensated(entities, softening)
 	}
-	if e.Position.Y > halfheight && e.Velocity.Y > 0 {
-		e.Velocity = e.Velocity.InvertY()
-		e.Velocity = e.Velocity.Scalarmul(damping)
+	return units.Diagnose(entities, softening)
+}
+
+// Return the domain tiling the plane under a periodic boundary, or the zero Domain for any other boundary
+func periodicdomain(simulation *physics.Simulation) physics.Domain {
+	if boundary, ok := simulation.Boundary.(physics.PeriodicBoundary); ok {
+		return boundary.Domain
+	}
+	return physics.Domain{}
+}
+
+// Title an entity table column with the symbol of its unit
+func header(col int) string {
+	return fmt.Sprintf("%v (%v)", fields[col].title, units.Symbol(fields[col].dimension))
+}
+
+// Return the planar entities to draw, seen through the camera for a three dimensional simulation
+func visible(simulation *physics.Simulation) []*physics.Entity {
+	if simulation3d != nil {
+		return camera.Project(simulation3d.Entities)
 	}
+	return simulation.Entities
 }
 
 // Draw all entities with black for position, red for velocity, and blue for acceleration
@@ -150,9 +378,9 @@ func drawentities(entities []*physics.Entity) {
 }
 
 func drawposition(entity *physics.Entity, drawable *gdk.Drawable, gc *gdk.GC) {
-	radius := math.Sqrt(entity.Mass)
-	startx := utils.RoundInt(centerfloatx(entity.Position.X) - (radius / 2))
-	starty := utils.RoundInt(centerfloaty(entity.Position.Y) - (radius / 2))
+	radius := 2 * inplayground(entity.Radius, physics.Dimensions.Length)
+	startx := utils.RoundInt(centerfloatx(inplayground(entity.Position.X, physics.Dimensions.Length)) - (radius / 2))
+	starty := utils.RoundInt(centerfloaty(inplayground(entity.Position.Y, physics.Dimensions.Length)) - (radius / 2))
 
 	// Draw at least 1 pixel for position
 	if radius < 1 {
@@ -163,18 +391,20 @@ func drawposition(entity *physics.Entity, drawable *gdk.Drawable, gc *gdk.GC) {
 }
 
 func drawvelocity(entity *physics.Entity, drawable *gdk.Drawable, gc *gdk.GC) {
-	startx := utils.RoundInt(entity.Position.X)
-	starty := utils.RoundInt(entity.Position.Y)
-	endx := utils.RoundInt(entity.Position.X + entity.Velocity.X)
-	endy := utils.RoundInt(entity.Position.Y + entity.Velocity.Y)
+	x, y := inplayground(entity.Position.X, physics.Dimensions.Length), inplayground(entity.Position.Y, physics.Dimensions.Length)
+	startx := utils.RoundInt(x)
+	starty := utils.RoundInt(y)
+	endx := utils.RoundInt(x + inplayground(entity.Velocity.X, physics.Dimensions.Velocity))
+	endy := utils.RoundInt(y + inplayground(entity.Velocity.Y, physics.Dimensions.Velocity))
 	drawable.DrawLine(gc, centerx(startx), centery(starty), centerx(endx), centery(endy))
 }
 
 func drawacceleration(entity *physics.Entity, drawable *gdk.Drawable, gc *gdk.GC) {
-	startx := utils.RoundInt(entity.Position.X)
-	starty := utils.RoundInt(entity.Position.Y)
-	endx := utils.RoundInt(entity.Position.X + entity.Acceleration.X)
-	endy := utils.RoundInt(entity.Position.Y + entity.Acceleration.Y)
+	x, y := inplayground(entity.Position.X, physics.Dimensions.Length), inplayground(entity.Position.Y, physics.Dimensions.Length)
+	startx := utils.RoundInt(x)
+	starty := utils.RoundInt(y)
+	endx := utils.RoundInt(x + inplayground(entity.Acceleration.X, physics.Dimensions.Acceleration))
+	endy := utils.RoundInt(y + inplayground(entity.Acceleration.Y, physics.Dimensions.Acceleration))
 	drawable.DrawLine(gc, centerx(startx), centery(starty), centerx(endx), centery(endy))
 }
 
@@ -196,12 +426,19 @@ func centerfloaty(y float64) float64 {
 
 func main() {
 	var autoupdating bool = false
-	var autoticker *time.Ticker
+	var autoticks int
 	var entries [][]*gtk.Entry = make([][]*gtk.Entry, entitylimit)
 	for i := 0; i < entitylimit; i++ {
 		entries[i] = make([]*gtk.Entry, entityfields)
 	}
-	var entities []*physics.Entity = initentities(entries)
+	var headers []*gtk.Label = make([]*gtk.Label, entityfields)
+	var simulation *physics.Simulation = physics.NewSimulation(initentities(entries))
+	simulation.Integrator = integrators[0].integrator
+	simulation.Boundary = boundaries[0].build(damping, 0)
+	simulation.Watchdog = &physics.Watchdog{MaxDrift: maxdrift, Policy: policies[0]}
+	simulation.Units = units
+	simulation.Dt = inunits(tick, physics.Dimensions.Time)
+	reference = diagnose(simulation.Entities)
 
 	// Initialize gtk
 	gtk.Init(nil)
@@ -230,10 +467,14 @@ func main() {
 	drawingarea.SetSizeRequest(width, height)
 	drawingarea.ModifyBG(gtk.STATE_NORMAL, gdk.NewColor("white"))
 	drawingarea.Connect("expose_event", func() {
-		drawentities(entities)
+		drawentities(visible(simulation))
 	})
 	davbox.PackStart(drawingarea, true, true, 0)
 
+	// STATUS LABEL
+	statuslabel = gtk.NewLabel(status(simulation))
+	davbox.Add(statuslabel)
+
 	// TICK SPEED SLIDER
 	ticksliderhbox := gtk.NewHBox(false, 1)
 
@@ -246,13 +487,313 @@ func main() {
 	ticksliderhbox.Add(tickslider)
 	davbox.Add(ticksliderhbox)
 
+	// INTEGRATOR SELECTION
+	integratorhbox := gtk.NewHBox(false, 1)
+	integratorhbox.Add(gtk.NewLabel("Integrator"))
+
+	integratorcombo := gtk.NewComboBoxText()
+	for _, choice := range integrators {
+		integratorcombo.AppendText(choice.name)
+	}
+	integratorcombo.SetActive(0)
+	integratorcombo.Connect("changed", func() {
+		simulation.Integrator = integrators[integratorcombo.GetActive()].integrator
+		statuslabel.SetText(status(simulation))
+	})
+	integratorhbox.Add(integratorcombo)
+	davbox.Add(integratorhbox)
+
+	// SOLVER SELECTION
+	solverhbox := gtk.NewHBox(false, 1)
+	solverhbox.Add(gtk.NewLabel("Force solver"))
+
+	solvercombo := gtk.NewComboBoxText()
+	for _, choice := range solvers {
+		solvercombo.AppendText(choice.name)
+	}
+	solvercombo.SetActive(0)
+	solverhbox.Add(solvercombo)
+
+	solverhbox.Add(gtk.NewLabel("Opening angle"))
+	thetaspin := gtk.NewSpinButtonWithRange(0, 2, 0.1)
+	thetaspin.SetValue(0.5)
+	solverhbox.Add(thetaspin)
+
+	solverhbox.Add(gtk.NewLabel("Expansion order"))
+	orderspin := gtk.NewSpinButtonWithRange(1, 20, 1)
+	orderspin.SetValue(8)
+	solverhbox.Add(orderspin)
+
+	// Say when the chosen solver is swapped for one that works with the boundary
+	solvernote := gtk.NewLabel("")
+	solverhbox.Add(solvernote)
+	davbox.Add(solverhbox)
+
+	// SOFTENING SELECTION
+	softeninghbox := gtk.NewHBox(false, 1)
+	softeninghbox.Add(gtk.NewLabel("Softening"))
+
+	kernelcombo := gtk.NewComboBoxText()
+	for _, kernel := range kernels {
+		kernelcombo.AppendText(kernel.String())
+	}
+	kernelcombo.SetActive(0)
+	softeninghbox.Add(kernelcombo)
+
+	softeninghbox.Add(gtk.NewLabel("Length (px)"))
+	softeningspin := gtk.NewSpinButtonWithRange(0, 100, 0.5)
+	softeningspin.SetValue(0)
+	softeninghbox.Add(softeningspin)
+
+	compensatedcheck := gtk.NewCheckButtonWithLabel("Compensated sums")
+	softeninghbox.Add(compensatedcheck)
+
+	davbox.Add(softeninghbox)
+
+	// Rebuild the solver whenever any solver or softening control changes
+	updatesolver := func() {
+		settings := solversettings{
+			softening:   physics.Softening{Kernel: kernels[kernelcombo.GetActive()], Length: inunits(softeningspin.GetValue(), physics.Dimensions.Length)},
+			theta:       thetaspin.GetValue(),
+			order:       int(orderspin.GetValue()),
+			compensated: compensatedcheck.GetActive(),
+		}
+		_, settings.periodic = simulation.Boundary.(physics.PeriodicBoundary)
+		simulation.Solver = solvers[solvercombo.GetActive()].build(settings)
+		solvernote.SetText("")
+		if _, replaced := simulation.Solver.(physics.MinimumImageSolver); replaced {
+			solvernote.SetText("Replaced by minimum image summation for the periodic boundary")
+		}
+
+		// Only direct summation in open space sums with compensation, so the checkbox does nothing elsewhere
+		_, compensable := simulation.Solver.(physics.ParallelSolver)
+		compensatedcheck.SetSensitive(compensable && simulation3d == nil)
+
+		// Softening changes the potential energy, so measure drift from here on
+		softening = settings.softening
+		compensated = settings.compensated && compensable
+		reference = diagnose(simulation.Entities)
+		simulation.Watchdog.Softening = softening
+		simulation.Watchdog.Compensated = compensated
+		simulation.Watchdog.Reset()
+		if simulation3d != nil {
+			simulation3d.Softening = softening
+			reference3d = simulation3d.Energy()
+		}
+		statuslabel.SetText(status(simulation))
+	}
+	solvercombo.Connect("changed", updatesolver)
+	thetaspin.Connect("value-changed", updatesolver)
+	orderspin.Connect("value-changed", updatesolver)
+	kernelcombo.Connect("changed", updatesolver)
+	softeningspin.Connect("value-changed", updatesolver)
+	compensatedcheck.Connect("toggled", updatesolver)
+
+	// BOUNDARY SELECTION
+	boundaryhbox := gtk.NewHBox(false, 1)
+	boundaryhbox.Add(gtk.NewLabel("Boundary"))
+
+	boundarycombo := gtk.NewComboBoxText()
+	for _, choice := range boundaries {
+		boundarycombo.AppendText(choice.name)
+	}
+	boundarycombo.SetActive(0)
+	boundaryhbox.Add(boundarycombo)
+
+	boundaryhbox.Add(gtk.NewLabel("Wall restitution"))
+	wallspin := gtk.NewSpinButtonWithRange(0, 1, 0.05)
+	wallspin.SetValue(damping)
+	boundaryhbox.Add(wallspin)
+
+	boundaryhbox.Add(gtk.NewLabel("Wall friction"))
+	frictionspin := gtk.NewSpinButtonWithRange(0, 1, 0.05)
+	frictionspin.SetValue(0)
+	boundaryhbox.Add(frictionspin)
+	davbox.Add(boundaryhbox)
+
+	// Rebuild the boundary whenever any boundary control changes, and the solver since periodic boundaries
+	// change how forces reach across the edges
+	updateboundary := func() {
+		simulation.Boundary = boundaries[boundarycombo.GetActive()].build(wallspin.GetValue(), frictionspin.GetValue())
+		updatesolver()
+	}
+	boundarycombo.Connect("changed", updateboundary)
+	wallspin.Connect("value-changed", updateboundary)
+	frictionspin.Connect("value-changed", updateboundary)
+
+	// COLLISION SELECTION
+	collisionhbox := gtk.NewHBox(false, 1)
+	collisionhbox.Add(gtk.NewLabel("Collisions"))
+
+	collidercombo := gtk.NewComboBoxText()
+	for _, choice := range colliders {
+		collidercombo.AppendText(choice.name)
+	}
+	collidercombo.SetActive(0)
+	collisionhbox.Add(collidercombo)
+
+	collisionhbox.Add(gtk.NewLabel("Restitution"))
+	restitutionspin := gtk.NewSpinButtonWithRange(0, 1, 0.05)
+	restitutionspin.SetValue(1)
+	collisionhbox.Add(restitutionspin)
+	davbox.Add(collisionhbox)
+
+	// Rebuild the collider whenever any collision control changes, starting its records over, and whenever the
+	// boundary changes since entities touch across periodic edges
+	updatecollider := func() {
+		simulation.Collider = colliders[collidercombo.GetActive()].build(restitutionspin.GetValue(), periodicdomain(simulation))
+		statuslabel.SetText(status(simulation))
+	}
+	collidercombo.Connect("changed", updatecollider)
+	restitutionspin.Connect("value-changed", updatecollider)
+	boundarycombo.Connect("changed", updatecollider)
+
+	// WATCHDOG SELECTION
+	watchdoghbox := gtk.NewHBox(false, 1)
+	watchdoghbox.Add(gtk.NewLabel("On blow-up"))
+
+	policycombo := gtk.NewComboBoxText()
+	for _, policy := range policies {
+		policycombo.AppendText(policy.String())
+	}
+	policycombo.SetActive(0)
+	watchdoghbox.Add(policycombo)
+
+	watchdoghbox.Add(gtk.NewLabel("Max energy drift"))
+	driftspin := gtk.NewSpinButtonWithRange(0, 1, 0.01)
+	driftspin.SetValue(maxdrift)
+	watchdoghbox.Add(driftspin)
+	davbox.Add(watchdoghbox)
+
+	// Rebuild the watchdog whenever any watchdog control changes, starting its checks over
+	updatewatchdog := func() {
+		simulation.Watchdog = &physics.Watchdog{MaxDrift: driftspin.GetValue(), Softening: softening, Compensated: compensated, Policy: policies[policycombo.GetActive()]}
+		statuslabel.SetText(status(simulation))
+	}
+	policycombo.Connect("changed", updatewatchdog)
+	driftspin.Connect("value-changed", updatewatchdog)
+
+	// UNITS SELECTION
+	unitshbox := gtk.NewHBox(false, 1)
+	unitshbox.Add(gtk.NewLabel("Units"))
+
+	unitscombo := gtk.NewComboBoxText()
+	for _, choice := range unitsystems {
+		unitscombo.AppendText(fmt.Sprintf("%v (%v, %v, %v)", choice, choice.Symbol(physics.Dimensions.Length), choice.Symbol(physics.Dimensions.Mass), choice.Symbol(physics.Dimensions.Time)))
+	}
+	unitscombo.SetActive(0)
+	unitshbox.Add(unitscombo)
+	davbox.Add(unitshbox)
+
+	// Switch the gravitational constant and the unit labels whenever the units change, entities already in the
+	// table are taken to be in the new units. The drawing area, tick, softening length and shattering energy stay
+	// the same in playground units, so the boundary, solver and collider are rebuilt with them in the new units
+	unitscombo.Connect("changed", func() {
+		units = unitsystems[unitscombo.GetActive()]
+		area = physics.NewDomain(inunits(float64(width), physics.Dimensions.Length), inunits(float64(height), physics.Dimensions.Length))
+		simulation.Units = units
+		simulation.Dt = inunits(tick, physics.Dimensions.Time)
+		updateblocksteps()
+		if simulation3d != nil {
+			simulation3d.Units = units
+			simulation3d.Dt = inunits(tick, physics.Dimensions.Time)
+		}
+		updateboundary()
+		updatecollider()
+		for col, label := range headers {
+			label.SetText(header(col))
+		}
+		statuslabel.SetText(status(simulation))
+	})
+
+	// CENTER OF MASS FRAME
+	framehbox := gtk.NewHBox(false, 1)
+	framecheck := gtk.NewCheckButtonWithLabel("Center of mass frame on reset")
+	framehbox.Add(framecheck)
+	framelabel := gtk.NewLabel("")
+	framehbox.Add(framelabel)
+	davbox.Add(framehbox)
+
+	// THREE DIMENSIONS
+	viewhbox := gtk.NewHBox(false, 1)
+	threedcheck := gtk.NewCheckButtonWithLabel("3D on reset")
+	viewhbox.Add(threedcheck)
+
+	viewhbox.Add(gtk.NewLabel("View"))
+	viewcombo := gtk.NewComboBoxText()
+	for _, choice := range views {
+		viewcombo.AppendText(choice.name)
+	}
+	viewcombo.SetActive(0)
+	viewhbox.Add(viewcombo)
+
+	viewhbox.Add(gtk.NewLabel("Yaw"))
+	yawspin := gtk.NewSpinButtonWithRange(-180, 180, 5)
+	yawspin.SetValue(0)
+	viewhbox.Add(yawspin)
+
+	viewhbox.Add(gtk.NewLabel("Pitch"))
+	pitchspin := gtk.NewSpinButtonWithRange(-180, 180, 5)
+	pitchspin.SetValue(0)
+	viewhbox.Add(pitchspin)
+	davbox.Add(viewhbox)
+
+	// Turn the camera whenever the angles change, and snap the angles onto a coordinate plane when one is chosen
+	updatecamera := func() {
+		camera = physics.Camera{Yaw: yawspin.GetValue() * math.Pi / 180, Pitch: pitchspin.GetValue() * math.Pi / 180}
+		statuslabel.SetText(status(simulation))
+		drawingarea.QueueDraw()
+	}
+	yawspin.Connect("value-changed", updatecamera)
+	pitchspin.Connect("value-changed", updatecamera)
+	viewcombo.Connect("changed", func() {
+		view := views[viewcombo.GetActive()].camera
+		yawspin.SetValue(view.Yaw * 180 / math.Pi)
+		pitchspin.SetValue(view.Pitch * 180 / math.Pi)
+	})
+
 	// BUTTONS
 	buttons := gtk.NewHBox(false, 1)
 
 	// RESET MENU ITEM
 	resetbutton := gtk.NewButtonWithLabel("Reset")
 	resetbutton.Clicked(func() {
-		entities = initentities(entries)
+		simulation.Entities = initentities(entries)
+		simulation.Time = 0
+
+		// Optionally put the barycenter at rest at the origin, saying how far everything moved
+		framelabel.SetText("")
+		if framecheck.GetActive() {
+			center, velocity := physics.CenterOfMassFrame(simulation.Entities)
+			framelabel.SetText(fmt.Sprintf("Shifted positions by (%.4g, %.4g) and velocities by (%.4g, %.4g)", -center.X, -center.Y, -velocity.X, -velocity.Y))
+		}
+		simulation.Collider = colliders[collidercombo.GetActive()].build(restitutionspin.GetValue(), periodicdomain(simulation))
+		reference = diagnose(simulation.Entities)
+		simulation.Watchdog.Reset()
+
+		// Optionally run the entities in three dimensions instead, with the softening of the planar solver
+		simulation3d = nil
+		if threedcheck.GetActive() {
+			simulation3d = physics.NewSimulation3D(initentities3d(entries))
+			simulation3d.Softening = softening
+			simulation3d.Units = units
+			simulation3d.Dt = inunits(tick, physics.Dimensions.Time)
+
+			// The three dimensional entities are built afresh, so they need moving into their own barycentric frame
+			if framecheck.GetActive() {
+				center, velocity := physics.CenterOfMassFrame3D(simulation3d.Entities)
+				framelabel.SetText(fmt.Sprintf("Shifted positions by (%.4g, %.4g, %.4g) and velocities by (%.4g, %.4g, %.4g)", -center.X, -center.Y, -center.Z, -velocity.X, -velocity.Y, -velocity.Z))
+			}
+			reference3d = simulation3d.Energy()
+		}
+
+		// Three dimensional simulations only use velocity Verlet with direct summation in open space
+		for _, planar := range []*gtk.HBox{integratorhbox, solverhbox, boundaryhbox, collisionhbox, watchdoghbox} {
+			planar.SetSensitive(simulation3d == nil)
+		}
+		_, compensable := simulation.Solver.(physics.ParallelSolver)
+		compensatedcheck.SetSensitive(compensable && simulation3d == nil)
+		statuslabel.SetText(status(simulation))
 		drawingarea.QueueDraw()
 	})
 	buttons.Add(resetbutton)
@@ -260,31 +801,35 @@ func main() {
 	// TICK MENU ITEM
 	tickbutton := gtk.NewButtonWithLabel("Tick")
 	tickbutton.Clicked(func() {
-		updateentities(entities)
+		updateentities(simulation)
 	})
 	buttons.Add(tickbutton)
 
 	// AUTOUPDATE MENU ITEM
 	autotickbutton := gtk.NewToggleButtonWithLabel("AutoUpdate")
 	autotickbutton.Clicked(func() {
-		// Stop the previous ticker if it exists
-		if autoticker != nil {
-			autoticker.Stop()
-		}
+		// Retire the previous timeout if there is one, it stops itself at its next call
+		autoticks++
 
 		if autoupdating {
 			// Toggle autoupdating state
 			autoupdating = false
 		} else {
-			// Start the ticker
-			autoticker = time.NewTicker(time.Duration(tickslider.GetValue()) * time.Millisecond)
-
-			// Spawn a goroutine that will run update entities every tick
-			go func() {
-				for _ = range autoticker.C {
-					updateentities(entities)
+			// Tick from the main loop every interval signified by the slider, so the widgets and the simulation
+			// are only touched from there, until auto update is stopped or restarted or the watchdog stops the
+			// simulation
+			ticks := autoticks
+			glib.TimeoutAdd(uint(tickslider.GetValue()), func() bool {
+				if ticks != autoticks {
+					return false
+				}
+				if updateentities(simulation) != nil {
+					// Pop the button back up, setting it emits a click that stops auto update
+					autotickbutton.SetActive(false)
+					return false
 				}
-			}()
+				return true
+			})
 
 			// Toggle autoupdating state
 			autoupdating = true
@@ -300,13 +845,11 @@ func main() {
 
 	// INITIALIZE LABELS FOR TABLE
 	titles := gtk.NewHBox(false, 1)
-	titles.Add(gtk.NewLabel("Mass"))
-	titles.Add(gtk.NewLabel("X-Pos"))
-	titles.Add(gtk.NewLabel("Y-Pos"))
-	titles.Add(gtk.NewLabel("X-Vel"))
-	titles.Add(gtk.NewLabel("Y-Vel"))
-	titles.Add(gtk.NewLabel("X-Acc"))
-	titles.Add(gtk.NewLabel("Y-Acc"))
+	for col := 0; col < entityfields; col++ {
+		// Hold reference to the label to retitle it when the units change
+		headers[col] = gtk.NewLabel(header(col))
+		titles.Add(headers[col])
+	}
 	entitiesvbox.Add(titles)
 
 	// INITIALIZE ENTRIES IN ROWS FOR TABLE
@@ -0,0 +1,255 @@
+package physics
+
+import "math"
+
+// Advancer is implemented by integrators that choose their own step sizes and
+// so can advance the entities across an arbitrary span of time in one call.
+// A Simulation only sees the end of the span, so its boundary, collider and
+// watchdog act once per call rather than after every internal step: entities
+// may pass through walls and each other and blow up in between, so keep the
+// spans short, like the ticks of the drawing, when those matter
+type Advancer interface {
+	Advance(entities []*Entity, solver Solver, duration float64)
+}
+
+// Dormand-Prince 5(4) tableau, the fifth order weights double as the final stage (first same as last)
+var dpnodes = []float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1}
+var dpcoefficients = [][]float64{
+	{},
+	{1.0 / 5},
+	{3.0 / 40, 9.0 / 40},
+	{44.0 / 45, -56.0 / 15, 32.0 / 9},
+	{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+	{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+	{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+}
+
+// Difference between the fifth and fourth order weights, used to estimate the local error
+var dperrors = []float64{71.0 / 57600, 0, -71.0 / 16695, 71.0 / 1920, -17253.0 / 339200, 22.0 / 525, -1.0 / 40}
+
+// Step size controller limits
+const (
+	dpsafety    float64 = 0.9
+	dpmingrowth float64 = 0.2
+	dpmaxgrowth float64 = 5
+)
+
+// DormandPrince is an adaptive embedded Runge-Kutta 5(4) integrator. Each step
+// is accepted only if the estimated local error of every position and velocity
+// component is within AbsTol plus RelTol of its magnitude, so steps shrink
+// during close encounters and grow again during quiet phases
+type DormandPrince struct {
+	AbsTol float64
+	RelTol float64
+
+	// Steps are never shrunk below MinStep, steps of that size are accepted regardless of error
+	MinStep float64
+
+	// Counts of steps taken since creation
+	Accepted int
+	Rejected int
+
+	// Step size suggested by the last accepted step
+	step float64
+}
+
+// NewDormandPrince returns a new DormandPrince integrator with the given absolute and relative tolerances
+func NewDormandPrince(abstol float64, reltol float64) *DormandPrince {
+	return &DormandPrince{AbsTol: abstol, RelTol: reltol, MinStep: 1e-12}
+}
+
+// Step advances the entities by dt, taking as many internal steps as the tolerances require
+func (i *DormandPrince) Step(entities []*Entity, solver Solver, dt float64) {
+	i.Advance(entities, solver, dt)
+}
+
+// Advance advances the entities by duration, taking as many internal steps as the tolerances require
+func (i *DormandPrince) Advance(entities []*Entity, solver Solver, duration float64) {
+	if duration <= 0 || len(entities) == 0 {
+		return
+	}
+
+	y := packstate(entities)
+	stages := make([][]float64, len(dpnodes))
+	for s := range stages {
+		stages[s] = make([]float64, len(y))
+	}
+	derivative(entities, solver, y, stages[0])
+	if !(i.step > 0) {
+		i.step = i.initialstep(entities, solver, y, stages[0])
+	}
+
+	trial := make([]float64, len(y))
+	elapsed := 0.0
+	for elapsed < duration {
+		// Clip the step to land exactly on duration
+		h := i.step
+		clipped := h >= duration-elapsed
+		if clipped {
+			h = duration - elapsed
+		}
+
+		// Evaluate the remaining stages, the last stage is evaluated at the fifth order solution
+		for s := 1; s < len(dpnodes); s++ {
+			for n := range y {
+				sum := 0.0
+				for k, a := range dpcoefficients[s] {
+					sum += a * stages[k][n]
+				}
+				trial[n] = y[n] + h*sum
+			}
+			derivative(entities, solver, trial, stages[s])
+		}
+
+		// Scaled root mean square of the local error estimate. With no absolute tolerance a component that is zero
+		// throughout the step has no scale to measure against, so it is left out
+		errnorm := 0.0
+		for n := range y {
+			scale := i.AbsTol + i.RelTol*math.Max(math.Abs(y[n]), math.Abs(trial[n]))
+			if scale == 0 {
+				continue
+			}
+			estimate := 0.0
+			for k, e := range dperrors {
+				estimate += e * stages[k][n]
+			}
+			errnorm += math.Pow(h*estimate/scale, 2)
+		}
+		errnorm = math.Sqrt(errnorm / float64(len(y)))
+
+		// A state that has blown up to infinity or NaN cannot be helped by any step size, so it is accepted as is
+		// for the caller to notice rather than retried forever
+		if !finite(trial) {
+			i.Accepted++
+			copy(y, trial)
+			copy(stages[0], stages[len(stages)-1])
+			break
+		}
+
+		// An undefined error is no reason to shrink the step, so it is accepted like a small one
+		if errnorm <= 1 || math.IsNaN(errnorm) || h <= i.MinStep {
+			i.Accepted++
+			elapsed += h
+			if clipped {
+				elapsed = duration
+			}
+			copy(y, trial)
+			copy(stages[0], stages[len(stages)-1])
+
+			// A step clipped to land on duration says little about the next step size, and an undefined error
+			// keeps the old one
+			if next := h * growth(errnorm, dpmaxgrowth); !clipped && next > 0 && !math.IsInf(next, 1) {
+				i.step = next
+			}
+		} else {
+			i.Rejected++
+			i.step = h * growth(errnorm, 1)
+			if !(i.step > i.MinStep) {
+				i.step = i.MinStep
+			}
+		}
+	}
+
+	// Leave the entities at the final state with the accelerations of that state
+	unpackstate(entities, y, stages[0])
+}
+
+// initialstep estimates a starting step size from the scale of the state and its derivatives
+func (i *DormandPrince) initialstep(entities []*Entity, solver Solver, y []float64, dy []float64) float64 {
+	scale := make([]float64, len(y))
+	for n := range y {
+		scale[n] = i.AbsTol + i.RelTol*math.Abs(y[n])
+	}
+	d0 := scalednorm(y, scale)
+	d1 := scalednorm(dy, scale)
+
+	h0 := 1e-6
+	if d0 >= 1e-5 && d1 >= 1e-5 {
+		h0 = 0.01 * d0 / d1
+	}
+
+	// Take an explicit Euler step to gauge how quickly the derivatives change
+	euler := make([]float64, len(y))
+	for n := range y {
+		euler[n] = y[n] + h0*dy[n]
+	}
+	dyeuler := make([]float64, len(y))
+	derivative(entities, solver, euler, dyeuler)
+	for n := range dyeuler {
+		dyeuler[n] -= dy[n]
+	}
+	d2 := scalednorm(dyeuler, scale) / h0
+
+	h1 := math.Max(1e-6, h0*1e-3)
+	if math.Max(d1, d2) > 1e-15 {
+		h1 = math.Pow(0.01/math.Max(d1, d2), 1.0/5)
+	}
+	return math.Max(math.Min(100*h0, h1), i.MinStep)
+}
+
+// growth returns the factor to scale a step by given its error norm, clamped to the controller limits
+func growth(errnorm float64, maxgrowth float64) float64 {
+	if errnorm == 0 {
+		return maxgrowth
+	}
+	return math.Min(maxgrowth, math.Max(dpmingrowth, dpsafety*math.Pow(errnorm, -1.0/5)))
+}
+
+// finite returns whether every component of v is neither infinite nor NaN
+func finite(v []float64) bool {
+	for _, x := range v {
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			return false
+		}
+	}
+	return true
+}
+
+// scalednorm returns the root mean square of v with each component divided by its scale, leaving out components
+// with no scale
+func scalednorm(v []float64, scale []float64) float64 {
+	sum := 0.0
+	for n := range v {
+		if scale[n] == 0 {
+			continue
+		}
+		sum += math.Pow(v[n]/scale[n], 2)
+	}
+	return math.Sqrt(sum / float64(len(v)))
+}
+
+// packstate returns the positions and velocities of the entities flattened into x, y, vx, vy quadruples
+func packstate(entities []*Entity) []float64 {
+	y := make([]float64, 4*len(entities))
+	for n, e := range entities {
+		y[4*n] = e.Position.X
+		y[4*n+1] = e.Position.Y
+		y[4*n+2] = e.Velocity.X
+		y[4*n+3] = e.Velocity.Y
+	}
+	return y
+}
+
+// unpackstate sets the positions and velocities of the entities from a packed state, and their accelerations
+// from the matching packed derivative
+func unpackstate(entities []*Entity, y []float64, dy []float64) {
+	for n, e := range entities {
+		e.Position = NewPoint(y[4*n], y[4*n+1])
+		e.Velocity = NewVector2D(y[4*n+2], y[4*n+3])
+		e.Acceleration = NewVector2D(dy[4*n+2], dy[4*n+3])
+	}
+}
+
+// derivative fills dy with the time derivative of the packed state y
+func derivative(entities []*Entity, solver Solver, y []float64, dy []float64) {
+	for n, e := range entities {
+		e.Position = NewPoint(y[4*n], y[4*n+1])
+	}
+	solver.Accelerate(entities)
+	for n, e := range entities {
+		dy[4*n] = y[4*n+2]
+		dy[4*n+1] = y[4*n+3]
+		dy[4*n+2] = e.Acceleration.X
+		dy[4*n+3] = e.Acceleration.Y
+	}
+}
@@ -0,0 +1,136 @@
+package physics
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// eccentricorbit returns a light planet starting at apocenter of an eccentric orbit around a heavy star,
+// along with the period of that orbit
+func eccentricorbit(radius float64, speedfraction float64) ([]*Entity, float64) {
+	starmass := 1000.0
+	gm := G * starmass
+	speed := speedfraction * math.Sqrt(gm/radius)
+	star := NewEntity(starmass, 0, 0, 0, 0, 0, 0)
+	planet := NewEntity(1e-6, radius, 0, 0, speed, 0, 0)
+	semimajor := -gm / (2 * (speed*speed/2 - gm/radius))
+	return []*Entity{star, planet}, 2 * math.Pi * math.Sqrt(math.Pow(semimajor, 3)/gm)
+}
+
+func TestDormandPrinceTolerance(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		tolerance float64
+		expected  float64
+	}{
+		{1e-4, 1e-2},
+		{1e-6, 1e-5},
+		{1e-9, 1e-8},
+	}
+
+	for _, c := range cases {
+		radius := 100.0
+		entities, period := circularorbit(radius)
+		integrator := NewDormandPrince(c.tolerance, c.tolerance)
+		integrator.Advance(entities, DirectSolver{}, period)
+		drift := math.Abs(entities[0].Distance(entities[1])-radius) / radius
+		if drift > c.expected {
+			t.Errorf("Integrating a circular orbit with tolerance %v drifted by %v - expected at most %v", c.tolerance, drift, c.expected)
+		}
+		if integrator.Accepted == 0 {
+			t.Errorf("Integrating a circular orbit with tolerance %v accepted no steps", c.tolerance)
+		}
+	}
+}
+
+func TestDormandPrinceAdvance(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entity   *Entity
+		duration float64
+		expected Point
+	}{
+		{NewEntity(1, 0, 0, 1, 2, 0, 0), 0.5, NewPoint(0.5, 1)},
+		{NewEntity(1, 1, 1, -2, 0, 0, 0), 10, NewPoint(-19, 1)},
+		{NewEntity(1, 3, 4, 0, 0, 0, 0), 1, NewPoint(3, 4)},
+		{NewEntity(1, 3, 4, 5, 6, 0, 0), 0, NewPoint(3, 4)},
+	}
+
+	for _, c := range cases {
+		NewDormandPrince(1e-6, 1e-6).Advance([]*Entity{c.entity}, DirectSolver{}, c.duration)
+		if pointsinequal(c.entity.Position, c.expected, testprecision) {
+			t.Errorf("Advancing %v by %v got %v - expected %v", c.entity, c.duration, c.entity.Position, c.expected)
+		}
+	}
+}
+
+func TestDormandPrinceStepSize(t *testing.T) {
+	t.Parallel()
+	entities, period := eccentricorbit(200, 0.3)
+	integrator := NewDormandPrince(1e-8, 1e-8)
+
+	// Starting at apocenter, half a period later the planet is at pericenter and a full period later back at apocenter
+	integrator.Advance(entities, DirectSolver{}, period/2)
+	pericenterstep := integrator.step
+	integrator.Advance(entities, DirectSolver{}, period/2)
+	apocenterstep := integrator.step
+
+	if pericenterstep*10 > apocenterstep {
+		t.Errorf("Step size at pericenter %v not much smaller than at apocenter %v", pericenterstep, apocenterstep)
+	}
+	if drift := math.Abs(entities[1].Position.X-200) / 200; drift > 1e-5 {
+		t.Errorf("Integrating an eccentric orbit drifted by %v - expected at most %v", drift, 1e-5)
+	}
+}
+
+func TestDormandPrinceNaN(t *testing.T) {
+	t.Parallel()
+	cases := []*Entity{
+		NewEntity(1, math.NaN(), 0, 0, 0, 0, 0),
+		NewEntity(1, 0, 0, math.Inf(1), 0, 0, 0),
+	}
+
+	for _, c := range cases {
+		entities := []*Entity{NewEntity(1000, 0, 0, 0, 0, 0, 0), c}
+		integrator := NewDormandPrince(1e-6, 1e-6)
+		done := make(chan bool)
+		go func() {
+			integrator.Advance(entities, DirectSolver{}, 1)
+			done <- true
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Advancing a blown up entity %v did not return", c)
+		}
+		if integrator.Accepted != 1 {
+			t.Errorf("Advancing a blown up entity %v took %v steps - expected to give up after 1", c, integrator.Accepted)
+		}
+	}
+}
+
+func TestDormandPrinceRelativeTolerance(t *testing.T) {
+	t.Parallel()
+
+	// Bodies on the x axis keep y components of exactly zero, which a purely relative tolerance cannot scale
+	entities := []*Entity{NewEntity(1000, 0, 0, 0, 0, 0, 0), NewEntity(1, 100, 0, 0, 0, 0, 0)}
+	integrator := NewDormandPrince(0, 1e-6)
+	done := make(chan bool)
+	go func() {
+		integrator.Advance(entities, DirectSolver{}, 0.01)
+		done <- true
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Advancing with only a relative tolerance did not return")
+	}
+	if integrator.Accepted > 100 || integrator.Rejected > 100 {
+		t.Errorf("Advancing with only a relative tolerance took %v steps and rejected %v - expected a handful", integrator.Accepted, integrator.Rejected)
+	}
+	if x := entities[1].Position.X; x >= 100 || math.IsNaN(x) || entities[1].Position.Y != 0 {
+		t.Errorf("Advancing with only a relative tolerance: got position %v - expected to fall towards the star along the x axis", entities[1].Position)
+	}
+}
@@ -0,0 +1,160 @@
+package physics
+
+import "math"
+
+// Quadtree cells are not split past this depth so coincident entities share a leaf instead of recursing forever
+const maxquaddepth int = 48
+
+// BarnesHutSolver approximates the gravitational acceleration of every entity
+// with a quadtree, aggregating each distant cell into a single mass at its
+// center of mass. A cell is treated as distant when its width divided by its
+// distance is below the opening angle Theta, so a Theta of 0 reduces to direct
+// summation and larger values trade accuracy for speed
+type BarnesHutSolver struct {
+	Theta     float64
+	Softening Softening
+}
+
+// quadnode is a square cell of a quadtree holding the total mass and center of mass of the entities within it
+type quadnode struct {
+	centerx  float64
+	centery  float64
+	half     float64
+	mass     float64
+	massx    float64
+	massy    float64
+	entities []*Entity
+	children *[4]quadnode
+}
+
+// Accelerate updates the acceleration of every entity from a quadtree of all entities in the slice
+func (s BarnesHutSolver) Accelerate(entities []*Entity) {
+	root := buildquadtree(entities)
+	for _, e := range entities {
+		ax, ay := root.accelerate(e, s.Theta*s.Theta, s.Softening)
+		e.Acceleration = NewVector2D(ax, ay)
+	}
+}
+
+// buildquadtree returns the root of a quadtree holding every entity, sized to the square bounding the entities
+func buildquadtree(entities []*Entity) *quadnode {
+	minx, miny := math.Inf(1), math.Inf(1)
+	maxx, maxy := math.Inf(-1), math.Inf(-1)
+	for _, e := range entities {
+		minx, maxx = math.Min(minx, e.Position.X), math.Max(maxx, e.Position.X)
+		miny, maxy = math.Min(miny, e.Position.Y), math.Max(maxy, e.Position.Y)
+	}
+
+	root := &quadnode{}
+	if len(entities) == 0 {
+		return root
+	}
+	root.centerx, root.centery = (minx+maxx)/2, (miny+maxy)/2
+	root.half = math.Max(math.Max(maxx-minx, maxy-miny)/2, 1e-9) * (1 + 1e-9)
+	for _, e := range entities {
+		root.insert(e, 0)
+	}
+	root.aggregate()
+	return root
+}
+
+// insert places the entity in the leaf of the tree containing its position, splitting leaves as needed
+func (n *quadnode) insert(e *Entity, depth int) {
+	if n.children == nil {
+		n.entities = append(n.entities, e)
+		if len(n.entities) == 1 || depth >= maxquaddepth {
+			return
+		}
+
+		// Split the leaf and push its entities down into the children
+		n.children = &[4]quadnode{}
+		quarter := n.half / 2
+		for q := range n.children {
+			n.children[q].half = quarter
+			n.children[q].centerx = n.centerx + quarter*float64(2*(q&1)-1)
+			n.children[q].centery = n.centery + quarter*float64(2*(q>>1)-1)
+		}
+		entities := n.entities
+		n.entities = nil
+		for _, existing := range entities {
+			n.children[n.quadrant(existing)].insert(existing, depth+1)
+		}
+		return
+	}
+	n.children[n.quadrant(e)].insert(e, depth+1)
+}
+
+// quadrant returns the index of the child cell containing the entity's position
+func (n *quadnode) quadrant(e *Entity) int {
+	q := 0
+	if e.Position.X >= n.centerx {
+		q |= 1
+	}
+	if e.Position.Y >= n.centery {
+		q |= 2
+	}
+	return q
+}
+
+// aggregate sums the mass and mass weighted position of every cell from the leaves up
+func (n *quadnode) aggregate() {
+	n.mass, n.massx, n.massy = 0, 0, 0
+	if n.children == nil {
+		for _, e := range n.entities {
+			n.mass += e.Mass
+			n.massx += e.Mass * e.Position.X
+			n.massy += e.Mass * e.Position.Y
+		}
+		return
+	}
+	for q := range n.children {
+		child := &n.children[q]
+		child.aggregate()
+		n.mass += child.mass
+		n.massx += child.massx
+		n.massy += child.massy
+	}
+}
+
+// contains reports whether the point lies within the cell
+func (n *quadnode) contains(p Point) bool {
+	return math.Abs(p.X-n.centerx) <= n.half && math.Abs(p.Y-n.centery) <= n.half
+}
+
+// accelerate returns the acceleration the cell exerts on the entity, opening cells whose squared width over
+// squared distance is not below thetasquared and any cell containing the entity itself
+func (n *quadnode) accelerate(e *Entity, thetasquared float64, softening Softening) (float64, float64) {
+	if n.mass == 0 {
+		return 0, 0
+	}
+
+	if n.children == nil {
+		ax, ay := 0.0, 0.0
+		for _, other := range n.entities {
+			if other == e {
+				continue
+			}
+			dx, dy := other.Position.X-e.Position.X, other.Position.Y-e.Position.Y
+			g, _ := softening.factor(math.Sqrt(dx*dx + dy*dy))
+			ax += G * other.Mass * g * dx
+			ay += G * other.Mass * g * dy
+		}
+		return ax, ay
+	}
+
+	dx, dy := n.massx/n.mass-e.Position.X, n.massy/n.mass-e.Position.Y
+	squared := dx*dx + dy*dy
+	width := 2 * n.half
+	if !n.contains(e.Position) && width*width < thetasquared*squared {
+		g, _ := softening.factor(math.Sqrt(squared))
+		return G * n.mass * g * dx, G * n.mass * g * dy
+	}
+
+	ax, ay := 0.0, 0.0
+	for q := range n.children {
+		cx, cy := n.children[q].accelerate(e, thetasquared, softening)
+		ax += cx
+		ay += cy
+	}
+	return ax, ay
+}
@@ -0,0 +1,79 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// randomentities returns count entities of random mass scattered randomly over a square of the given half width
+func randomentities(count int, halfwidth float64, seed int64) []*Entity {
+	random := rand.New(rand.NewSource(seed))
+	entities := make([]*Entity, count)
+	for i := range entities {
+		x := (2*random.Float64() - 1) * halfwidth
+		y := (2*random.Float64() - 1) * halfwidth
+		entities[i] = NewEntity(1+99*random.Float64(), x, y, 0, 0, 0, 0)
+	}
+	return entities
+}
+
+// accelerationerror returns the largest error of the entities accelerations computed by the solver relative to
+// direct summation, as a fraction of the root mean square direct acceleration
+func accelerationerror(entities []*Entity, solver Solver, softening Softening) float64 {
+	DirectSolver{Softening: softening}.Accelerate(entities)
+	expected := make([]Vector2D, len(entities))
+	rms := 0.0
+	for n, e := range entities {
+		expected[n] = e.Acceleration
+		rms += e.Acceleration.Dotproduct(e.Acceleration)
+	}
+	rms = math.Sqrt(rms / float64(len(entities)))
+	if rms == 0 {
+		rms = 1
+	}
+
+	solver.Accelerate(entities)
+	worst := 0.0
+	for n, e := range entities {
+		worst = math.Max(worst, e.Acceleration.Subtract(expected[n]).Length()/rms)
+	}
+	return worst
+}
+
+func TestBarnesHutSolverAccuracy(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		theta     float64
+		softening Softening
+		count     int
+		tolerance float64
+	}{
+		{0, Softening{}, 200, 1e-12},
+		{0.3, Softening{}, 200, 1e-2},
+		{0.5, Softening{}, 500, 3e-2},
+		{0.5, Softening{Plummer, 5}, 500, 1e-1},
+		{1, Softening{Spline, 5}, 500, 5e-1},
+		{0.5, Softening{}, 1, 0},
+	}
+
+	for _, c := range cases {
+		entities := randomentities(c.count, 300, 1)
+		worst := accelerationerror(entities, BarnesHutSolver{Theta: c.theta, Softening: c.softening}, c.softening)
+		if worst > c.tolerance {
+			t.Errorf("Computing Barnes-Hut accelerations of %v entities with theta %v and %v: error %v - expected at most %v", c.count, c.theta, c.softening, worst, c.tolerance)
+		}
+	}
+}
+
+func TestBarnesHutSolverCoincident(t *testing.T) {
+	t.Parallel()
+	entities := []*Entity{NewEntity(1, 5, 5, 0, 0, 0, 0), NewEntity(1, 5, 5, 0, 0, 0, 0), NewEntity(1, -5, 5, 0, 0, 0, 0)}
+	BarnesHutSolver{Theta: 0.5}.Accelerate(entities)
+	expected := []Vector2D{NewVector2D(-6.6783, 0), NewVector2D(-6.6783, 0), NewVector2D(13.3567, 0)}
+	for n, e := range entities {
+		if accelerationsinequal(e, &Entity{Acceleration: expected[n]}, 4) {
+			t.Errorf("Computing Barnes-Hut accelerations of coincident entities: got %v - expected %v", e.Acceleration, expected[n])
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package physics
+
+import "math"
+
+// BlockHermite is the fourth order Hermite predictor-corrector integrator with
+// individual hierarchical block timesteps. Each entity steps by MaxStep halved
+// its level number of times, chosen by the Aarseth criterion scaled by Eta, so
+// only the block of entities due at a given time is corrected while every other
+// entity has its position and velocity predicted. MaxStep should be a power of
+// two so block times are exact. Every call to Advance starts the blocks over
+// from synchronized entities, so MaxStep should be no shorter than the
+// duration advanced per call or even the slowest entities take several steps.
+// Solvers that are not JerkSolvers are bypassed in favour of direct summation
+// with the same softening, over nearest images for the periodic PMSolver
+type BlockHermite struct {
+	Eta      float64
+	MaxStep  float64
+	MaxLevel int
+
+	// Counts of block steps and of individual entity steps since creation
+	Blocks int
+	Steps  int
+
+	// Level of every entity after the last call to Advance
+	levels map[*Entity]int
+}
+
+// blockstate is the last corrected state of an entity and when it was corrected
+type blockstate struct {
+	position     Point
+	velocity     Vector2D
+	acceleration Vector2D
+	jerk         Vector2D
+	time         float64
+	level        int
+}
+
+// NewBlockHermite returns a new BlockHermite integrator with the given accuracy parameter, largest step and
+// number of times that step may be halved
+func NewBlockHermite(eta float64, maxstep float64, maxlevel int) *BlockHermite {
+	return &BlockHermite{Eta: eta, MaxStep: maxstep, MaxLevel: maxlevel, levels: make(map[*Entity]int)}
+}
+
+// Level returns the timestep level of the entity, its step being MaxStep halved that many times
+func (i *BlockHermite) Level(e *Entity) int {
+	return i.levels[e]
+}
+
+// Step advances the entities by dt, synchronizing all of them at the end
+func (i *BlockHermite) Step(entities []*Entity, solver Solver, dt float64) {
+	i.Advance(entities, solver, dt)
+}
+
+// Advance advances the entities by duration in blocks, finishing with a step that synchronizes every entity at duration
+func (i *BlockHermite) Advance(entities []*Entity, solver Solver, duration float64) {
+	if duration <= 0 || len(entities) == 0 {
+		return
+	}
+
+	jerksolver := jerksolverof(solver)
+
+	// Entities start synchronized at time 0, which is aligned with every block, keeping their previous levels
+	jerksolver.AccelerateWithJerk(entities, entities)
+	states := make([]blockstate, len(entities))
+	for n, e := range entities {
+		level, known := i.levels[e]
+		if !known {
+			level = i.quantize(entitystartingstep(e))
+		}
+		states[n] = blockstate{e.Position, e.Velocity, e.Acceleration, e.Jerk, 0, level}
+	}
+
+	active := make([]*Entity, 0, len(entities))
+	for {
+		// The next block is every entity due at the earliest time, unless that passes duration
+		next := math.Inf(1)
+		for _, state := range states {
+			next = math.Min(next, state.time+i.blockstep(state.level))
+		}
+		synchronizing := next >= duration
+		if synchronizing {
+			next = duration
+		}
+
+		active = active[:0]
+		for n, e := range entities {
+			state := states[n]
+			if synchronizing || state.time+i.blockstep(state.level) == next {
+				active = append(active, e)
+			}
+			e.Position, e.Velocity = hermitepredict(state.position, state.velocity, state.acceleration, state.jerk, next-state.time)
+		}
+
+		jerksolver.AccelerateWithJerk(active, entities)
+		for n, e := range entities {
+			state := &states[n]
+			if !synchronizing && state.time+i.blockstep(state.level) != next {
+				continue
+			}
+
+			var criterion float64
+			e.Position, e.Velocity, criterion = hermitecorrect(i.Eta, state.position, state.velocity, state.acceleration, state.jerk, e.Acceleration, e.Jerk, next-state.time)
+			state.position, state.velocity, state.acceleration, state.jerk = e.Position, e.Velocity, e.Acceleration, e.Jerk
+			state.time = next
+			state.level = i.relevel(state.level, criterion, next, synchronizing)
+		}
+
+		i.Blocks++
+		i.Steps += len(active)
+		if synchronizing {
+			break
+		}
+	}
+
+	// Remember levels for the next call, forgetting entities no longer simulated
+	i.levels = make(map[*Entity]int, len(entities))
+	for n, e := range entities {
+		i.levels[e] = states[n].level
+	}
+
+	// Leave the accelerations and jerks consistent with the synchronized state
+	jerksolver.AccelerateWithJerk(entities, entities)
+}
+
+// blockstep returns the step size of a level
+func (i *BlockHermite) blockstep(level int) float64 {
+	return math.Ldexp(i.MaxStep, -level)
+}
+
+// quantize returns the level of the largest block step no larger than the given step
+func (i *BlockHermite) quantize(step float64) int {
+	if math.IsNaN(step) || step >= i.MaxStep {
+		return 0
+	}
+	level := int(math.Ceil(math.Log2(i.MaxStep / step)))
+	if level > i.MaxLevel {
+		return i.MaxLevel
+	}
+	return level
+}
+
+// relevel returns the next level of an entity given its Aarseth criterion. Steps may shrink freely but only
+// double, one level at a time, when the current time is aligned with the doubled step. After synchronizing
+// every entity is aligned, as the next call starts over at time 0
+func (i *BlockHermite) relevel(level int, criterion float64, time float64, synchronized bool) int {
+	if math.IsInf(criterion, 1) || math.IsNaN(criterion) {
+		return level
+	}
+	wanted := i.quantize(criterion)
+	if wanted >= level || synchronized {
+		return wanted
+	}
+	if math.Mod(time, i.blockstep(level-1)) == 0 {
+		return level - 1
+	}
+	return level
+}
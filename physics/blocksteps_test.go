@@ -0,0 +1,100 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"math"
+	"testing"
+)
+
+// hierarchicalsystem returns a tight binary star orbited by a distant light planet
+func hierarchicalsystem() []*Entity {
+	speed := math.Sqrt(G * 1000 / 20)
+	return []*Entity{
+		NewEntity(1000, 5, 0, 0, speed, 0, 0),
+		NewEntity(1000, -5, 0, 0, -speed, 0, 0),
+		NewEntity(1, 300, 0, 0, math.Sqrt(G*2000/300), 0, 0),
+	}
+}
+
+func TestBlockHermiteAdvance(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entity   *Entity
+		duration float64
+		expected Point
+	}{
+		{NewEntity(1, 0, 0, 1, 2, 0, 0), 0.5, NewPoint(0.5, 1)},
+		{NewEntity(1, 1, 1, -2, 0, 0, 0), 10, NewPoint(-19, 1)},
+		{NewEntity(1, 0, 0, 3, 0, 0, 0), 0.01, NewPoint(0.03, 0)},
+		{NewEntity(1, 3, 4, 5, 6, 0, 0), 0, NewPoint(3, 4)},
+	}
+
+	for _, c := range cases {
+		NewBlockHermite(0.01, 1.0/16, 20).Advance([]*Entity{c.entity}, DirectSolver{}, c.duration)
+		if pointsinequal(c.entity.Position, c.expected, testprecision) {
+			t.Errorf("Advancing %v by %v got %v - expected %v", c.entity, c.duration, c.entity.Position, c.expected)
+		}
+	}
+}
+
+func TestBlockHermiteLevels(t *testing.T) {
+	t.Parallel()
+	entities := hierarchicalsystem()
+	initial := totalenergy(entities)
+	integrator := NewBlockHermite(0.01, 1.0/16, 20)
+	integrator.Advance(entities, DirectSolver{}, 2)
+
+	binary, planet := integrator.Level(entities[0]), integrator.Level(entities[2])
+	if binary <= planet {
+		t.Errorf("Binary star level %v not deeper than distant planet level %v", binary, planet)
+	}
+
+	// Most blocks should only step the binary
+	if integrator.Steps > 2*integrator.Blocks+integrator.Blocks/4 {
+		t.Errorf("Took %v entity steps in %v blocks - expected the planet to sit out most blocks", integrator.Steps, integrator.Blocks)
+	}
+
+	if drift := math.Abs((totalenergy(entities) - initial) / initial); drift > 1e-5 {
+		t.Errorf("Integrating a hierarchical system drifted energy by %v - expected at most %v", drift, 1e-5)
+	}
+}
+
+func TestBlockHermiteSynchronization(t *testing.T) {
+	t.Parallel()
+	testprecision := 2
+
+	// Stopping at arbitrary output times should land on the same state as advancing straight through
+	outputs := []float64{0.013, 0.25, 0.5, 0.77, 1}
+	synchronized := hierarchicalsystem()
+	integrator := NewBlockHermite(0.001, 1.0/16, 20)
+	elapsed := 0.0
+	for _, output := range outputs {
+		integrator.Advance(synchronized, DirectSolver{}, output-elapsed)
+		elapsed = output
+	}
+
+	straight := hierarchicalsystem()
+	NewBlockHermite(0.001, 1.0/16, 20).Advance(straight, DirectSolver{}, 1)
+
+	for n := range straight {
+		if pointsinequal(synchronized[n].Position, NewPoint(utils.RoundPrecision(straight[n].Position.X, testprecision), utils.RoundPrecision(straight[n].Position.Y, testprecision)), testprecision) {
+			t.Errorf("Synchronizing at %v got %v - expected %v", outputs, synchronized[n].Position, straight[n].Position)
+		}
+	}
+}
+
+func TestBlockHermiteSolverFallback(t *testing.T) {
+	t.Parallel()
+
+	// Softened solvers without jerks are replaced by direct summation keeping their softening
+	softening := Softening{Spline, 20}
+	entities, expected := hierarchicalsystem(), hierarchicalsystem()
+	NewBlockHermite(0.01, 1.0/16, 20).Advance(entities, BarnesHutSolver{Theta: 0.5, Softening: softening}, 1)
+	NewBlockHermite(0.01, 1.0/16, 20).Advance(expected, DirectSolver{Softening: softening}, 1)
+	for n := range entities {
+		if entities[n].Position != expected[n].Position {
+			t.Errorf("Advancing entity %v with a softened Barnes-Hut solver: got %v - expected %v", n, entities[n], expected[n])
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package physics
+
+import "math"
+
+// Boundary decides what happens to entities at the edges of the simulated region over a step of dt, returning the
+// entities that remain afterwards
+type Boundary interface {
+	Apply(entities []*Entity, dt float64) []*Entity
+}
+
+// Rebounder is a Boundary that can stop entities at the time they reached it within a step, so the rest of the step
+// can be integrated again from there instead of assuming a straight line
+type Rebounder interface {
+	Boundary
+	Rebound(entities []*Entity, dt float64) []Bounce
+}
+
+// Bounce is an entity a Rebounder sent back from a wall, along with how much of the step it has left to move away
+// from it
+type Bounce struct {
+	Entity    *Entity
+	Remainder float64
+}
+
+// Maximum number of times a ReflectiveBoundary bounces an entity between walls in one step, enough for entities that
+// overshoot across the whole domain within a step
+const maxwallbounces int = 16
+
+// ReflectiveBoundary bounces entities off the walls of Domain. Restitution scales the speed of an entity towards a
+// wall as it bounces, 1 for perfectly elastic walls and 0 for walls that stop entities dead, while Friction is the
+// coefficient of the wall slowing the entity along the wall by that fraction of the bounce's change in speed
+type ReflectiveBoundary struct {
+	Domain      Domain
+	Restitution float64
+	Friction    float64
+}
+
+// Apply bounces every entity that went out through a wall during the last step of dt. Entities are taken to have
+// moved in a straight line over the step, so each bounces at the time it reached the wall and spends the rest of the
+// step moving away from it with its bounced velocity, rather than being left outside
+func (b ReflectiveBoundary) Apply(entities []*Entity, dt float64) []*Entity {
+	for _, e := range entities {
+		remainder := dt
+		for bounce := 0; bounce < maxwallbounces; bounce++ {
+			since, outside := b.rebound(e, remainder)
+			if !outside {
+				break
+			}
+			e.Position = e.Position.Add(e.Velocity.Scalarmul(since))
+			remainder = since
+		}
+		b.clamp(e)
+	}
+	return entities
+}
+
+// Rebound moves every entity that went out through a wall during the last step of dt back to where it reached the
+// wall, bounces it, and returns them with the time left in the step for the caller to move them on
+func (b ReflectiveBoundary) Rebound(entities []*Entity, dt float64) []Bounce {
+	bounces := []Bounce{}
+	for _, e := range entities {
+		if since, outside := b.rebound(e, dt); outside {
+			bounces = append(bounces, Bounce{e, since})
+		}
+	}
+	return bounces
+}
+
+// rebound bounces an entity outside the domain off the wall it went through first, moving it back along a straight
+// line to where it reached the wall, and returns how long before the end of the step that was. The time is limited to
+// dt, so entities that were already outside before the step are put on the wall rather than rewound further
+func (b ReflectiveBoundary) rebound(e *Entity, dt float64) (float64, bool) {
+	p, v := &e.Position, &e.Velocity
+	since, outside, headingout := 0.0, false, false
+	var normal, tangent, normalvelocity, tangentvelocity *float64
+	var wall float64
+	cross := func(position, tangentposition, velocity, tangentialvelocity *float64, limit float64, beyond bool) {
+		if !beyond {
+			return
+		}
+		// Entities outside but not heading further out crossed before the step
+		crossed, out := math.Inf(1), *velocity*(*position-limit) > 0
+		if out {
+			crossed = (*position - limit) / *velocity
+		}
+		if !outside || crossed > since {
+			since, outside, headingout = crossed, true, out
+			normal, tangent, normalvelocity, tangentvelocity, wall = position, tangentposition, velocity, tangentialvelocity, limit
+		}
+	}
+	cross(&p.X, &p.Y, &v.X, &v.Y, b.Domain.MinX, p.X < b.Domain.MinX)
+	cross(&p.X, &p.Y, &v.X, &v.Y, b.Domain.MaxX, p.X > b.Domain.MaxX)
+	cross(&p.Y, &p.X, &v.Y, &v.X, b.Domain.MinY, p.Y < b.Domain.MinY)
+	cross(&p.Y, &p.X, &v.Y, &v.X, b.Domain.MaxY, p.Y > b.Domain.MaxY)
+	if !outside {
+		return 0, false
+	}
+
+	since = math.Max(0, math.Min(since, dt))
+	*normal, *tangent = wall, *tangent-*tangentvelocity*since
+	if headingout {
+		*normalvelocity, *tangentvelocity = b.reflect(*normalvelocity, *tangentvelocity)
+	}
+	return since, true
+}
+
+// reflect bounces an entity off a wall, given its normal and tangential velocity, and returns them afterwards
+func (b ReflectiveBoundary) reflect(normalvelocity float64, tangentvelocity float64) (float64, float64) {
+	bounced := -b.Restitution * normalvelocity
+
+	// Friction can stop sliding along the wall but never reverse it
+	slowing := b.Friction * math.Abs(bounced-normalvelocity)
+	slid := 0.0
+	if math.Abs(tangentvelocity) > slowing {
+		slid = tangentvelocity - math.Copysign(slowing, tangentvelocity)
+	}
+	return bounced, slid
+}
+
+// clamp puts an entity still outside the domain after the most bounces back on its walls
+func (b ReflectiveBoundary) clamp(e *Entity) {
+	e.Position.X = math.Max(b.Domain.MinX, math.Min(e.Position.X, b.Domain.MaxX))
+	e.Position.Y = math.Max(b.Domain.MinY, math.Min(e.Position.Y, b.Domain.MaxY))
+}
+
+// PeriodicBoundary tiles the plane with Domain, so entities leaving through one edge come back through the opposite
+// edge. Pair it with MinimumImageSolver or PMSolver so forces also reach across the edges
+type PeriodicBoundary struct {
+	Domain Domain
+}
+
+// Apply wraps every entity back into the domain
+func (b PeriodicBoundary) Apply(entities []*Entity, dt float64) []*Entity {
+	for _, e := range entities {
+		e.Position = b.Domain.Wrap(e.Position)
+	}
+	return entities
+}
+
+// AbsorbingBoundary removes entities that leave Domain
+type AbsorbingBoundary struct {
+	Domain Domain
+}
+
+// Apply returns the entities still within the domain
+func (b AbsorbingBoundary) Apply(entities []*Entity, dt float64) []*Entity {
+	remaining := make([]*Entity, 0, len(entities))
+	for _, e := range entities {
+		if b.Domain.Contains(e.Position) {
+			remaining = append(remaining, e)
+		}
+	}
+	return remaining
+}
+
+// OpenBoundary leaves the plane unbounded, entities go wherever they move
+type OpenBoundary struct{}
+
+// Apply returns the entities untouched
+func (b OpenBoundary) Apply(entities []*Entity, dt float64) []*Entity {
+	return entities
+}
+
+// MinimumImageSolver sums the gravitational acceleration of every pair of entities like DirectSolver, softened by
+// Softening, but as if Domain tiled the plane, with each entity pulled by the nearest image of every other
+type MinimumImageSolver struct {
+	Domain    Domain
+	Softening Softening
+}
+
+// Accelerate updates the acceleration of every entity from the nearest image of every other entity in the slice
+func (s MinimumImageSolver) Accelerate(entities []*Entity) {
+	for _, e1 := range entities {
+		e1.Acceleration = NewVector2D(0, 0)
+		for _, e2 := range entities {
+			if e1 == e2 {
+				continue
+			}
+			displacement := s.Domain.MinimumImage(e1.Position.DisplacementVector(e2.Position))
+			g, _ := s.Softening.factor(displacement.Length())
+			e1.Acceleration = e1.Acceleration.Add(displacement.Scalarmul(G * e2.Mass * g))
+		}
+	}
+}
+
+// AccelerateWithJerk updates the acceleration and jerk of every active entity from the nearest image of every other
+// entity in the slice
+func (s MinimumImageSolver) AccelerateWithJerk(active []*Entity, entities []*Entity) {
+	for _, e1 := range active {
+		e1.Acceleration = NewVector2D(0, 0)
+		e1.Jerk = NewVector2D(0, 0)
+		for _, e2 := range entities {
+			if e1 == e2 {
+				continue
+			}
+			displacement := s.Domain.MinimumImage(e1.Position.DisplacementVector(e2.Position))
+			relvelocity := e2.Velocity.Subtract(e1.Velocity)
+			g, rate := s.Softening.factor(displacement.Length())
+			gm := G * e2.Mass
+			e1.Acceleration = e1.Acceleration.Add(displacement.Scalarmul(gm * g))
+			e1.Jerk = e1.Jerk.Add(relvelocity.Scalarmul(gm * g)).Add(displacement.Scalarmul(gm * rate * displacement.Dotproduct(relvelocity)))
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"reflect"
+	"testing"
+)
+
+func TestBoundaryApply(t *testing.T) {
+	t.Parallel()
+	domain := NewDomain(10, 10)
+	entities := func() []*Entity {
+		return []*Entity{
+			NewEntity(1, 0, 0, 3, -3, 0, 0),
+			NewEntity(1, 6, 0, 2, 1, 0, 0),
+			NewEntity(1, 0, -7, 1, 2, 0, 0),
+			NewEntity(1, -6, 8, -4, 4, 0, 0),
+		}
+	}
+	cases := []struct {
+		boundary   Boundary
+		positions  []Point
+		velocities []Vector2D
+	}{
+		// Entities outside and heading further out bounce, coming back in by their overshoot scaled by restitution,
+		// while those already heading back in are put on the wall a step ago
+		{ReflectiveBoundary{Domain: domain, Restitution: 0.5},
+			[]Point{NewPoint(0, 0), NewPoint(4.5, 0), NewPoint(0, -3), NewPoint(-4.5, 3.5)},
+			[]Vector2D{NewVector2D(3, -3), NewVector2D(-1, 1), NewVector2D(1, 2), NewVector2D(2, -2)}},
+		{PeriodicBoundary{Domain: domain},
+			[]Point{NewPoint(0, 0), NewPoint(-4, 0), NewPoint(0, 3), NewPoint(4, -2)},
+			[]Vector2D{NewVector2D(3, -3), NewVector2D(2, 1), NewVector2D(1, 2), NewVector2D(-4, 4)}},
+		{AbsorbingBoundary{Domain: domain},
+			[]Point{NewPoint(0, 0)},
+			[]Vector2D{NewVector2D(3, -3)}},
+		{OpenBoundary{},
+			[]Point{NewPoint(0, 0), NewPoint(6, 0), NewPoint(0, -7), NewPoint(-6, 8)},
+			[]Vector2D{NewVector2D(3, -3), NewVector2D(2, 1), NewVector2D(1, 2), NewVector2D(-4, 4)}},
+	}
+
+	for _, c := range cases {
+		remaining := c.boundary.Apply(entities(), 1)
+		if len(remaining) != len(c.positions) {
+			t.Errorf("Applying %#v: got %v - expected %v entities", c.boundary, remaining, len(c.positions))
+			continue
+		}
+		for i, e := range remaining {
+			if !reflect.DeepEqual(e.Position, c.positions[i]) || !reflect.DeepEqual(e.Velocity, c.velocities[i]) {
+				t.Errorf("Applying %#v: entity %v got %v - expected position %v and velocity %v", c.boundary, i, e, c.positions[i], c.velocities[i])
+			}
+		}
+	}
+}
+
+func TestReflectiveBoundaryTimeOfImpact(t *testing.T) {
+	t.Parallel()
+	testprecision := 6
+	cases := []struct {
+		boundary ReflectiveBoundary
+		entity   *Entity
+		dt       float64
+		position Point
+		velocity Vector2D
+	}{
+		// Half a time unit past the wall, so half a time unit back in
+		{ReflectiveBoundary{Domain: NewDomain(10, 10), Restitution: 1}, NewEntity(1, 7, 1, 4, 2, 0, 0), 1, NewPoint(3, 1), NewVector2D(-4, 2)},
+		// Friction slows sliding along the wall by its share of the bounce
+		{ReflectiveBoundary{Domain: NewDomain(10, 10), Restitution: 1, Friction: 0.1}, NewEntity(1, 7, 1, 4, 2, 0, 0), 1, NewPoint(3, 0.6), NewVector2D(-4, 1.2)},
+		// But stops it rather than reversing it
+		{ReflectiveBoundary{Domain: NewDomain(10, 10), Restitution: 1, Friction: 0.25}, NewEntity(1, 7, 1, 4, 2, 0, 0), 1, NewPoint(3, 0), NewVector2D(-4, 0)},
+		// Entities that overshot far enough bounce between both walls
+		{ReflectiveBoundary{Domain: NewDomain(10, 10), Restitution: 1}, NewEntity(1, 27, 0, 10, 0, 0, 0), 3, NewPoint(3, 0), NewVector2D(-10, 0)},
+		// And out of corners
+		{ReflectiveBoundary{Domain: NewDomain(10, 10), Restitution: 0.5}, NewEntity(1, -6, -7, -2, -4, 0, 0), 1, NewPoint(-4.5, -4), NewVector2D(1, 2)},
+		// Entities already outside before the step are put on the wall rather than rewound past the other one
+		{ReflectiveBoundary{Domain: NewDomain(10, 10), Restitution: 1}, NewEntity(1, 1000, 1, 1, 0, 0, 0), 0.5, NewPoint(4.5, 1), NewVector2D(-1, 0)},
+		// Even when heading back in
+		{ReflectiveBoundary{Domain: NewDomain(10, 10), Restitution: 1}, NewEntity(1, 1000, 1, -1, 2, 0, 0), 0.5, NewPoint(4.5, 1), NewVector2D(-1, 2)},
+	}
+
+	for _, c := range cases {
+		c.boundary.Apply([]*Entity{c.entity}, c.dt)
+		if pointsinequal(c.entity.Position, c.position, testprecision) ||
+			utils.RoundPrecision(c.entity.Velocity.X, testprecision) != c.velocity.X ||
+			utils.RoundPrecision(c.entity.Velocity.Y, testprecision) != c.velocity.Y {
+			t.Errorf("Bouncing off %#v: got %v - expected position %v and velocity %v", c.boundary, c.entity, c.position, c.velocity)
+		}
+	}
+}
+
+func TestMinimumImageSolverAccelerate(t *testing.T) {
+	t.Parallel()
+
+	// Entities well inside the domain feel only each other's nearest images, which are the entities themselves
+	entities := randomentities(30, 100, 12)
+	solver := MinimumImageSolver{Domain: NewDomain(1000, 1000), Softening: Softening{Plummer, 1}}
+	if worst := accelerationerror(entities, solver, solver.Softening); worst > 1e-12 {
+		t.Errorf("Computing minimum image accelerations of nearby entities: error %v - expected at most 1e-12", worst)
+	}
+
+	// Entities either side of an edge pull towards it, as if they were neighbours
+	across := []*Entity{NewEntity(100, 95, 0, 0, 0, 0, 0), NewEntity(100, -95, 0, 0, 0, 0, 0)}
+	MinimumImageSolver{Domain: NewDomain(200, 200)}.Accelerate(across)
+	expected := G * 100 / (10 * 10)
+	if utils.RoundPrecision(across[0].Acceleration.X, 4) != expected || utils.RoundPrecision(across[1].Acceleration.X, 4) != -expected {
+		t.Errorf("Computing minimum image accelerations across an edge: got %v and %v - expected %v and %v", across[0].Acceleration, across[1].Acceleration, expected, -expected)
+	}
+}
+
+func TestMinimumImageSolverAccelerateWithJerk(t *testing.T) {
+	t.Parallel()
+	testprecision := 6
+
+	// Entities well inside the domain get the same jerks as from direct summation
+	entities := randomentities(20, 100, 13)
+	for _, e := range entities {
+		e.Velocity = NewVector2D(e.Position.Y/10, -e.Position.X/10)
+	}
+	expected := make([]Vector2D, len(entities))
+	DirectSolver{Softening: Softening{Plummer, 1}}.AccelerateWithJerk(entities, entities)
+	for n, e := range entities {
+		expected[n] = e.Jerk
+	}
+	MinimumImageSolver{Domain: NewDomain(1000, 1000), Softening: Softening{Plummer, 1}}.AccelerateWithJerk(entities, entities)
+	for n, e := range entities {
+		if utils.RoundPrecision(e.Jerk.X, testprecision) != utils.RoundPrecision(expected[n].X, testprecision) ||
+			utils.RoundPrecision(e.Jerk.Y, testprecision) != utils.RoundPrecision(expected[n].Y, testprecision) {
+			t.Errorf("Computing minimum image jerk of entity %v: got %v - expected %v", n, e.Jerk, expected[n])
+		}
+	}
+
+	// Entities closing in across an edge feel each other's pull growing
+	across := []*Entity{NewEntity(100, 95, 0, 1, 0, 0, 0), NewEntity(100, -95, 0, -1, 0, 0, 0)}
+	MinimumImageSolver{Domain: NewDomain(200, 200)}.AccelerateWithJerk(across, across)
+	if across[0].Acceleration.X <= 0 || across[0].Jerk.X <= 0 || across[1].Jerk.X >= 0 {
+		t.Errorf("Computing minimum image jerks across an edge: got %v and %v - expected growing pulls across it", across[0], across[1])
+	}
+}
+
+func TestSimulationBoundary(t *testing.T) {
+	t.Parallel()
+
+	// An entity flying out of an absorbing domain is removed once it leaves
+	simulation := NewSimulation([]*Entity{NewEntity(1, 0, 0, 10, 0, 0, 0), NewEntity(1e-6, 0, 50, 0, 0, 0, 0)})
+	simulation.Boundary = AbsorbingBoundary{Domain: NewDomain(200, 200)}
+	simulation.AdvanceTo(9)
+	if len(simulation.Entities) != 2 {
+		t.Errorf("Simulating inside an absorbing domain: got %v - expected both entities", simulation.Entities)
+	}
+	simulation.AdvanceTo(11)
+	if len(simulation.Entities) != 1 {
+		t.Errorf("Simulating out of an absorbing domain: got %v - expected one entity", simulation.Entities)
+	}
+
+	// An entity crossing the whole domain within a step still ends each step inside reflective walls
+	fast := NewEntity(1, 0, 0, 3000, 1700, 0, 0)
+	simulation = NewSimulation([]*Entity{fast})
+	simulation.Boundary = ReflectiveBoundary{Domain: NewDomain(20, 20), Restitution: 1}
+	for i := 0; i < 100; i++ {
+		simulation.Tick()
+		if !simulation.Boundary.(ReflectiveBoundary).Domain.Contains(fast.Position) || fast.Velocity.Length() != NewVector2D(3000, 1700).Length() {
+			t.Fatalf("Simulating a fast entity between reflective walls: got %v - expected it inside at full speed", fast)
+		}
+	}
+
+	// An entity that starts outside is brought back to the wall it is beyond, not rewound across the domain
+	outside := NewEntity(1, 1000, 0, 1, 0, 0, 0)
+	simulation = NewSimulation([]*Entity{outside})
+	simulation.Boundary = ReflectiveBoundary{Domain: NewDomain(640, 480), Restitution: 1}
+	simulation.Tick()
+	if utils.RoundPrecision(outside.Position.X, 6) != 319.99 || outside.Velocity.X != -1 {
+		t.Errorf("Simulating an entity starting outside reflective walls: got %v - expected it just inside the wall heading back", outside)
+	}
+
+	// Bounced entities are integrated over the rest of the step, still pulled by the others
+	star, planet := NewEntity(100, 0, 0, 0, 0, 0, 0), NewEntity(1e-6, 9.9, 0, 100, 0, 0, 0)
+	simulation = NewSimulation([]*Entity{star, planet})
+	simulation.Boundary = ReflectiveBoundary{Domain: NewDomain(20, 20), Restitution: 1}
+	simulation.Tick()
+	if planet.Velocity.X >= -100 || !simulation.Boundary.(ReflectiveBoundary).Domain.Contains(planet.Position) {
+		t.Errorf("Simulating a bounce in the pull of a star: got %v - expected it back inside, sped up towards the star", planet)
+	}
+}
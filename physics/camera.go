@@ -0,0 +1,44 @@
+package physics
+
+import "math"
+
+// Camera is an orthographic camera looking at three dimensional space, which it flattens onto the plane of the
+// screen. Yaw turns space about the z axis and Pitch then tilts it about the screen's x axis, both in radians. The
+// zero camera looks down the z axis onto the xy plane
+type Camera struct {
+	Yaw   float64
+	Pitch float64
+}
+
+// Cameras looking straight onto each of the coordinate planes, showing the first axis across the screen and the
+// second up or down it
+var (
+	ViewXY = Camera{}
+	ViewXZ = Camera{Pitch: -math.Pi / 2}
+	ViewYZ = Camera{Yaw: -math.Pi / 2, Pitch: -math.Pi / 2}
+)
+
+// View returns the vector turned to face the camera, with its depth into the screen along z
+func (c Camera) View(v Vector3D) Vector3D {
+	return v.RotateZ(c.Yaw).RotateX(c.Pitch)
+}
+
+// Project returns planar entities showing the entities as the camera sees them, with their positions, velocities
+// and accelerations flattened onto the screen and their masses and radii kept
+func (c Camera) Project(entities []*Entity3D) []*Entity {
+	projected := make([]*Entity, len(entities))
+	for n, e := range entities {
+		position := c.View(NewPoint3D(0, 0, 0).DisplacementVector(e.Position))
+		velocity := c.View(e.Velocity)
+		acceleration := c.View(e.Acceleration)
+		projected[n] = &Entity{
+			Mass:         e.Mass,
+			Radius:       e.Radius,
+			Position:     NewPoint(position.X, position.Y),
+			Velocity:     NewVector2D(velocity.X, velocity.Y),
+			Acceleration: NewVector2D(acceleration.X, acceleration.Y),
+			Jerk:         NewVector2D(0, 0),
+		}
+	}
+	return projected
+}
@@ -0,0 +1,53 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCameraView(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	v := NewVector3D(1, 2, 3)
+	cases := []struct {
+		camera   Camera
+		expected Vector3D
+	}{
+		{ViewXY, NewVector3D(1, 2, 3)},
+		{ViewXZ, NewVector3D(1, 3, -2)},
+		{ViewYZ, NewVector3D(2, 3, 1)},
+		{Camera{Yaw: math.Pi / 2}, NewVector3D(-2, 1, 3)},
+		{Camera{Pitch: math.Pi}, NewVector3D(1, -2, -3)},
+	}
+
+	for _, c := range cases {
+		viewed := c.camera.View(v)
+		if vectors3dinequal(viewed, c.expected, testprecision) {
+			t.Errorf("Viewing %v with %v got %v - expected %v", v, c.camera, viewed, c.expected)
+		}
+		if math.Abs(viewed.Length()-v.Length()) > 1e-12 {
+			t.Errorf("Viewing %v with %v changed its length to %v", v, c.camera, viewed.Length())
+		}
+	}
+}
+
+func TestCameraProject(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	entities := []*Entity3D{NewEntity3D(4, 1, 2, 3, 4, 5, 6, 7, 8, 9), NewEntity3D(9, -1, 0, 1, 0, 0, 0, 0, 0, 0)}
+	projected := ViewXZ.Project(entities)
+	expected := []*Entity{NewEntity(4, 1, 3, 4, 6, 7, 9), NewEntity(9, -1, 1, 0, 0, 0, 0)}
+	if len(projected) != len(expected) {
+		t.Fatalf("Projecting %v entities got %v", len(entities), len(projected))
+	}
+	for n, e := range projected {
+		if pointsinequal(e.Position, expected[n].Position, testprecision) ||
+			pointsinequal(NewPoint(e.Velocity.X, e.Velocity.Y), NewPoint(expected[n].Velocity.X, expected[n].Velocity.Y), testprecision) ||
+			accelerationsinequal(e, expected[n], testprecision) {
+			t.Errorf("Projecting %v got %v - expected %v", entities[n], e, expected[n])
+		}
+		if e.Mass != entities[n].Mass || e.Radius != entities[n].Radius {
+			t.Errorf("Projecting %v got mass %v and radius %v - expected %v and %v", entities[n], e.Mass, e.Radius, entities[n].Mass, entities[n].Radius)
+		}
+	}
+}
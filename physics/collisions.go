@@ -0,0 +1,343 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Collider resolves contacts between overlapping entities at the given simulated time, returning the entities
+// that remain afterwards
+type Collider interface {
+	Collide(entities []*Entity, time float64) []*Entity
+}
+
+// Collision records a contact resolved by a collider: when it happened, the entities involved and the body or
+// fragments that came out of it, if any
+type Collision struct {
+	Time         float64
+	Participants []*Entity
+	Result       *Entity
+	Fragments    []*Entity
+}
+
+// Merger is a Collider that merges every pair of overlapping entities into a single body, conserving mass, linear
+// momentum and center of mass, with a disk area equal to that of the pair. Domain, when set, tiles the plane like
+// PeriodicBoundary so entities also touch across its edges. Each merge is appended to Records
+type Merger struct {
+	Domain  Domain
+	Records []Collision
+}
+
+// Collide merges overlapping entities until none overlap, returning the survivors in their original order with each
+// merged body in place of the first of its pair
+func (m *Merger) Collide(entities []*Entity, time float64) []*Entity {
+	for i := 0; i < len(entities); i++ {
+		for j := i + 1; j < len(entities); j++ {
+			if !overlapping(entities[i], entities[j], m.Domain) {
+				continue
+			}
+
+			merged := merge(entities[i], entities[j], m.Domain)
+			m.Records = append(m.Records, Collision{Time: time, Participants: []*Entity{entities[i], entities[j]}, Result: merged})
+
+			// The merged body is bigger than either and may now reach entities anywhere in the slice, so start the
+			// checks over
+			remaining := make([]*Entity, 0, len(entities)-1)
+			remaining = append(remaining, entities[:j]...)
+			entities = append(remaining, entities[j+1:]...)
+			entities[i] = merged
+			i, j = -1, len(entities)
+		}
+	}
+	return entities
+}
+
+// overlapping returns whether the disks of two entities overlap, across the edges of a periodic domain
+func overlapping(e1 *Entity, e2 *Entity, domain Domain) bool {
+	return domain.separation(e1.Position, e2.Position).Length() < e1.Radius+e2.Radius
+}
+
+// merge returns a new entity with the combined mass of both entities at their center of mass, moving with their
+// combined momentum. Massless entities are weighted equally. The center of mass of a pair touching across the edges
+// of a periodic domain is wrapped back into it
+func merge(e1 *Entity, e2 *Entity, domain Domain) *Entity {
+	mass := e1.Mass + e2.Mass
+	w1, w2 := 0.5, 0.5
+	if mass != 0 {
+		w1, w2 = e1.Mass/mass, e2.Mass/mass
+	}
+	position := e1.Position.Add(domain.separation(e1.Position, e2.Position).Scalarmul(w2))
+	if domain.periodic() {
+		position = domain.Wrap(position)
+	}
+	return &Entity{
+		Mass:         mass,
+		Radius:       math.Hypot(e1.Radius, e2.Radius),
+		Position:     position,
+		Velocity:     e1.Velocity.Scalarmul(w1).Add(e2.Velocity.Scalarmul(w2)),
+		Acceleration: e1.Acceleration.Scalarmul(w1).Add(e2.Acceleration.Scalarmul(w2)),
+		Jerk:         NewVector2D(0, 0),
+	}
+}
+
+// Maximum passes a Bouncer makes over the contacts to settle bodies touching several others at once
+const bouncepasses int = 16
+
+// Bouncer is a Collider that treats entities as rigid disks, bouncing overlapping entities off each other with an
+// impulse along the line between their centers and pushing them apart until they just touch. Restitution is the
+// fraction of the approach speed kept after a bounce, 1 for perfectly elastic and 0 for bodies that stop dead
+// against each other. PairRestitution, when set, overrides Restitution for each pair it is given. Each pair that
+// bounces is appended to Records, with no Result since no new body is created. Domain, when set, tiles the plane
+// like PeriodicBoundary so entities also touch across its edges
+type Bouncer struct {
+	Restitution     float64
+	PairRestitution func(e1 *Entity, e2 *Entity) float64
+	Domain          Domain
+	Records         []Collision
+}
+
+// Collide bounces approaching overlapping entities off each other, sweeping over all contacts until none are still
+// approaching so bodies touching several others at once pass impulses along, then separates overlapping entities.
+// The same entities are returned
+func (b *Bouncer) Collide(entities []*Entity, time float64) []*Entity {
+	bounced := make(map[[2]*Entity]bool)
+	for pass := 0; pass < bouncepasses; pass++ {
+		approaching := false
+		for i, e1 := range entities {
+			for _, e2 := range entities[i+1:] {
+				if !overlapping(e1, e2, b.Domain) || !b.bounce(e1, e2) {
+					continue
+				}
+				approaching = true
+				if !bounced[[2]*Entity{e1, e2}] {
+					bounced[[2]*Entity{e1, e2}] = true
+					b.Records = append(b.Records, Collision{Time: time, Participants: []*Entity{e1, e2}})
+				}
+			}
+		}
+		if !approaching {
+			break
+		}
+	}
+
+	for pass := 0; pass < bouncepasses; pass++ {
+		separated := true
+		for i, e1 := range entities {
+			for _, e2 := range entities[i+1:] {
+				if overlapping(e1, e2, b.Domain) {
+					separate(e1, e2, b.Domain)
+					separated = false
+				}
+			}
+		}
+		if separated {
+			break
+		}
+	}
+	return entities
+}
+
+// bounce applies equal and opposite impulses along the contact normal to two entities approaching each other and
+// returns whether they were approaching
+func (b *Bouncer) bounce(e1 *Entity, e2 *Entity) bool {
+	normal := contactnormal(e1, e2, b.Domain)
+	approach := e2.Velocity.Subtract(e1.Velocity).Dotproduct(normal)
+	if approach >= 0 {
+		return false
+	}
+
+	restitution := b.Restitution
+	if b.PairRestitution != nil {
+		restitution = b.PairRestitution(e1, e2)
+	}
+
+	// Two massless entities have no momentum to exchange
+	inverse1, inverse2 := inversemass(e1), inversemass(e2)
+	if inverse1+inverse2 == 0 {
+		return false
+	}
+
+	// The impulse that turns the approach speed into restitution times the separation speed
+	impulse := -(1 + restitution) * approach / (inverse1 + inverse2)
+	e1.Velocity = e1.Velocity.Subtract(normal.Scalarmul(impulse * inverse1))
+	e2.Velocity = e2.Velocity.Add(normal.Scalarmul(impulse * inverse2))
+	return true
+}
+
+// inversemass returns one over the mass of the entity, or 0 for a massless entity so it bounces like an immovable one
+// rather than flying off infinitely fast
+func inversemass(e *Entity) float64 {
+	if e.Mass == 0 {
+		return 0
+	}
+	return 1 / e.Mass
+}
+
+// separate moves two overlapping entities apart along the contact normal until they just touch, moving the lighter
+// entity further so their center of mass stays put, and two massless entities equally
+func separate(e1 *Entity, e2 *Entity, domain Domain) {
+	normal := contactnormal(e1, e2, domain)
+	overlap := e1.Radius + e2.Radius - domain.separation(e1.Position, e2.Position).Length()
+	inverse1, inverse2 := inversemass(e1), inversemass(e2)
+	share := 0.5
+	if inverse1+inverse2 != 0 {
+		share = inverse1 / (inverse1 + inverse2)
+	}
+	e1.Position = e1.Position.Subtract(normal.Scalarmul(overlap * share))
+	e2.Position = e2.Position.Add(normal.Scalarmul(overlap * (1 - share)))
+}
+
+// contactnormal returns the unit vector from the first entity's center towards the second's nearest image in a
+// periodic domain, or along the x axis for coincident entities
+func contactnormal(e1 *Entity, e2 *Entity, domain Domain) Vector2D {
+	displacement := domain.separation(e1.Position, e2.Position)
+	if displacement.Length() == 0 {
+		return NewVector2D(1, 0)
+	}
+	return displacement.Normalize()
+}
+
+// Fragmenter is a Collider that shatters overlapping entities into a spray of fragments when their specific impact
+// energy, the kinetic energy of their approach in their center of mass frame per unit of combined mass, is at least
+// Threshold, and merges them like Merger below it. A shattering pair breaks into Fragments pieces at the threshold,
+// with the count growing in proportion to specific impact energy up to MaxFragments, and at least two. Fragment masses
+// are split in proportion to draws from MassDistribution, and the fragments fly outwards from the center of mass at
+// speeds in proportion to draws from SpeedDistribution, scaled so they carry Dispersion times the impact energy.
+// Mass, momentum and center of mass are conserved. Random draws the splits, seeded with 1 when nil. Domain, when
+// set, tiles the plane like PeriodicBoundary so entities also touch across its edges. Each merge or shattering is
+// appended to Records
+type Fragmenter struct {
+	Threshold         float64
+	Fragments         int
+	MaxFragments      int
+	Dispersion        float64
+	MassDistribution  func(random *rand.Rand) float64
+	SpeedDistribution func(random *rand.Rand) float64
+	Domain            Domain
+	Random            *rand.Rand
+	Records           []Collision
+}
+
+// ExponentialMasses draws fragment masses from the exponential distribution, which splits mass uniformly over all
+// possible splits. It is the default MassDistribution of a Fragmenter
+func ExponentialMasses(random *rand.Rand) float64 {
+	return random.ExpFloat64()
+}
+
+// UniformSpeeds draws fragment speeds uniformly between half and one and a half times the average. It is the
+// default SpeedDistribution of a Fragmenter
+func UniformSpeeds(random *rand.Rand) float64 {
+	return 0.5 + random.Float64()
+}
+
+// Collide shatters or merges overlapping entities until none overlap, returning the survivors in their original
+// order followed by any fragments
+func (f *Fragmenter) Collide(entities []*Entity, time float64) []*Entity {
+	for i := 0; i < len(entities); i++ {
+		for j := i + 1; j < len(entities); j++ {
+			e1, e2 := entities[i], entities[j]
+			if !overlapping(e1, e2, f.Domain) {
+				continue
+			}
+
+			record := Collision{Time: time, Participants: []*Entity{e1, e2}}
+			// Massless entities have no mass to split into fragments, so they always merge
+			if e1.Mass+e2.Mass > 0 && specificimpactenergy(e1, e2) >= f.Threshold {
+				record.Fragments = f.shatter(e1, e2)
+			} else {
+				record.Result = merge(e1, e2, f.Domain)
+				record.Fragments = []*Entity{record.Result}
+			}
+			f.Records = append(f.Records, record)
+
+			remaining := make([]*Entity, 0, len(entities)-2+len(record.Fragments))
+			remaining = append(remaining, entities[:i]...)
+			remaining = append(remaining, entities[i+1:j]...)
+			remaining = append(remaining, entities[j+1:]...)
+			entities = append(remaining, record.Fragments...)
+
+			// New bodies may reach entities anywhere in the slice, so start the checks over
+			i, j = -1, len(entities)
+		}
+	}
+	return entities
+}
+
+// shatter returns the fragments of two colliding entities
+func (f *Fragmenter) shatter(e1 *Entity, e2 *Entity) []*Entity {
+	if f.Random == nil {
+		f.Random = rand.New(rand.NewSource(1))
+	}
+	massdistribution, speeddistribution := f.MassDistribution, f.SpeedDistribution
+	if massdistribution == nil {
+		massdistribution = ExponentialMasses
+	}
+	if speeddistribution == nil {
+		speeddistribution = UniformSpeeds
+	}
+	mass := e1.Mass + e2.Mass
+	center := merge(e1, e2, f.Domain)
+
+	// A zero Threshold leaves nothing to scale the count by, so it always breaks into the most pieces
+	pieces := float64(f.Fragments) * specificimpactenergy(e1, e2) / f.Threshold
+	if math.IsNaN(pieces) || math.IsInf(pieces, 0) {
+		pieces = float64(f.MaxFragments)
+	}
+	if f.MaxFragments > 0 {
+		pieces = math.Min(pieces, float64(f.MaxFragments))
+	}
+	count := int(math.Max(2, math.Round(pieces)))
+
+	// Split the mass at random
+	masses := make([]float64, count)
+	total := 0.0
+	for k := range masses {
+		masses[k] = massdistribution(f.Random)
+		total += masses[k]
+	}
+	fragments := make([]*Entity, count)
+	largest := 0.0
+	for k := range fragments {
+		masses[k] *= mass / total
+		fragments[k] = NewEntity(masses[k], 0, 0, 0, 0, 0, 0)
+
+		// Share out the disk area of the merged pair by mass, whatever units the radii are in
+		fragments[k].Radius = center.Radius * math.Sqrt(masses[k]/mass)
+		largest = math.Max(largest, fragments[k].Radius)
+	}
+
+	// Space the fragments around a ring wide enough that none overlap, heading outwards at random speeds, then
+	// shift and correct them so their center of mass and momentum match the pair's
+	ring := largest / math.Sin(math.Pi/float64(count)) * (1 + 1e-9)
+	offsets := make([]Vector2D, count)
+	offset, momentum := NewVector2D(0, 0), NewVector2D(0, 0)
+	phase := f.Random.Float64()
+	for k, fragment := range fragments {
+		direction := NewVector2D(1, 0).Rotate(2 * math.Pi * (float64(k) + phase) / float64(count))
+		offsets[k] = direction.Scalarmul(ring)
+		fragment.Velocity = direction.Scalarmul(speeddistribution(f.Random))
+		offset = offset.Add(offsets[k].Scalarmul(fragment.Mass / mass))
+		momentum = momentum.Add(fragment.Velocity.Scalarmul(fragment.Mass / mass))
+	}
+	energy := 0.0
+	for _, fragment := range fragments {
+		fragment.Velocity = fragment.Velocity.Subtract(momentum)
+		energy += fragment.Mass * fragment.Velocity.Dotproduct(fragment.Velocity) / 2
+	}
+
+	scale := math.Sqrt(f.Dispersion * specificimpactenergy(e1, e2) * mass / energy)
+	for k, fragment := range fragments {
+		fragment.Position = center.Position.Add(offsets[k].Subtract(offset))
+		fragment.Velocity = center.Velocity.Add(fragment.Velocity.Scalarmul(scale))
+		fragment.Acceleration = center.Acceleration
+	}
+	return fragments
+}
+
+// specificimpactenergy returns the kinetic energy of two entities in their center of mass frame per unit of their
+// combined mass
+func specificimpactenergy(e1 *Entity, e2 *Entity) float64 {
+	mass := e1.Mass + e2.Mass
+	relative := e2.Velocity.Subtract(e1.Velocity)
+	return e1.Mass * e2.Mass / mass * relative.Dotproduct(relative) / 2 / mass
+}
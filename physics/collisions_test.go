@@ -0,0 +1,351 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMergerCollide(t *testing.T) {
+	t.Parallel()
+	testprecision := 6
+	cases := []struct {
+		entities []*Entity
+		expected []*Entity
+		merges   int
+	}{
+		// Apart
+		{[]*Entity{NewEntity(4, 0, 0, 1, 0, 0, 0), NewEntity(4, 3, 0, -1, 0, 0, 0)}, []*Entity{NewEntity(4, 0, 0, 1, 0, 0, 0), NewEntity(4, 3, 0, -1, 0, 0, 0)}, 0},
+		// Overlapping pair
+		{[]*Entity{NewEntity(4, 0, 0, 1, 0, 0, 0), NewEntity(12, 1, 2, -1, 2, 0, 0)}, []*Entity{NewEntity(16, 0.75, 1.5, -0.5, 1.5, 0, 0)}, 1},
+		// The merged body grows to reach a third entity
+		{[]*Entity{NewEntity(16, 0, 0, 0, 0, 0, 0), NewEntity(16, 3.9, 0, 0, 0, 0, 0), NewEntity(32, -3, 0, 0, 1, 0, 0)}, []*Entity{NewEntity(64, -0.525, 0, 0, 0.5, 0, 0)}, 2},
+		// The merged body grows to reach an entity checked before it
+		{[]*Entity{NewEntity(4, 0, 0, 0, 0, 0, 0), NewEntity(144, 10, 0, 0, 0, 0, 0), NewEntity(256, 10.5, 0, 0, 0, 0, 0)}, []*Entity{NewEntity(404, 10.217822, 0, 0, 0, 0, 0)}, 2},
+		// Two separate pairs
+		{[]*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 100, 0, 0, 0, 0, 0), NewEntity(1, 0.5, 0, 0, 0, 0, 0), NewEntity(1, 100, 0.5, 0, 0, 0, 0)}, []*Entity{NewEntity(2, 0.25, 0, 0, 0, 0, 0), NewEntity(2, 100, 0.25, 0, 0, 0, 0)}, 2},
+	}
+
+	for _, c := range cases {
+		merger := &Merger{}
+		survivors := merger.Collide(c.entities, 1.5)
+		if len(survivors) != len(c.expected) || len(merger.Records) != c.merges {
+			t.Errorf("Merging %v: got %v after %v merges - expected %v after %v merges", c.entities, survivors, len(merger.Records), c.expected, c.merges)
+			continue
+		}
+		for i := range survivors {
+			if pointsinequal(survivors[i].Position, c.expected[i].Position, testprecision) ||
+				utils.RoundPrecision(survivors[i].Velocity.X, testprecision) != c.expected[i].Velocity.X ||
+				utils.RoundPrecision(survivors[i].Velocity.Y, testprecision) != c.expected[i].Velocity.Y ||
+				survivors[i].Mass != c.expected[i].Mass ||
+				utils.RoundPrecision(survivors[i].Radius, testprecision) != utils.RoundPrecision(c.expected[i].Radius, testprecision) {
+				t.Errorf("Merging %v: got %v - expected %v", c.entities, survivors[i], c.expected[i])
+			}
+		}
+		for _, record := range merger.Records {
+			if record.Time != 1.5 || len(record.Participants) != 2 || record.Result == nil {
+				t.Errorf("Merging %v: got record %v - expected a merge of two entities at 1.5", c.entities, record)
+			}
+		}
+	}
+}
+
+func TestSimulationMerger(t *testing.T) {
+	t.Parallel()
+
+	// Two bodies falling into each other end up as one at rest at their common center of mass
+	entities := []*Entity{NewEntity(100, -20, 0, 0, 0, 0, 0), NewEntity(300, 20, 0, 0, 0, 0, 0)}
+	merger := &Merger{}
+	simulation := NewSimulation(entities)
+	simulation.Collider = merger
+	simulation.AdvanceTo(5)
+
+	if len(simulation.Entities) != 1 || len(merger.Records) != 1 {
+		t.Fatalf("Simulating two bodies falling together: got %v after %v merges - expected one body after one merge", simulation.Entities, len(merger.Records))
+	}
+	merged := simulation.Entities[0]
+	if merged.Mass != 400 || merger.Records[0].Result != merged {
+		t.Errorf("Simulating two bodies falling together: got %v - expected the merged body of mass 400", merged)
+	}
+	if merged.Velocity.Length() > 1e-9 || utils.RoundPrecision(merged.Position.X, 6) != 10 {
+		t.Errorf("Simulating two bodies falling together: got %v - expected a body at rest at the center of mass", merged)
+	}
+	if merger.Records[0].Time <= 0 || merger.Records[0].Time > 5 {
+		t.Errorf("Simulating two bodies falling together: merged at %v - expected a time within the run", merger.Records[0].Time)
+	}
+}
+
+func TestBouncerCollide(t *testing.T) {
+	t.Parallel()
+	testprecision := 6
+	cases := []struct {
+		restitution float64
+		entities    []*Entity
+		expected    []Vector2D
+		bounces     int
+	}{
+		// Equal masses swap velocities in an elastic head on collision
+		{1, []*Entity{NewEntity(16, 0, 0, 1, 0, 0, 0), NewEntity(16, 3.5, 0, -1, 0, 0, 0)}, []Vector2D{NewVector2D(-1, 0), NewVector2D(1, 0)}, 1},
+		// And move off together when perfectly inelastic
+		{0, []*Entity{NewEntity(16, 0, 0, 1, 0, 0, 0), NewEntity(16, 3.5, 0, -1, 0, 0, 0)}, []Vector2D{NewVector2D(0, 0), NewVector2D(0, 0)}, 1},
+		// A light body rebounds off a heavy one at half speed
+		{0.5, []*Entity{NewEntity(1, 0, 0, 2, 0, 0, 0), NewEntity(1e12, 5e5, 0, 0, 0, 0, 0)}, []Vector2D{NewVector2D(-1, 0), NewVector2D(0, 0)}, 1},
+		// Only the velocity along the line between centers is affected
+		{1, []*Entity{NewEntity(16, 0, 0, 1, 1, 0, 0), NewEntity(16, 3.5, 0, 0, 0, 0, 0)}, []Vector2D{NewVector2D(0, 1), NewVector2D(1, 0)}, 1},
+		// Overlapping entities moving apart are left moving apart
+		{1, []*Entity{NewEntity(16, 0, 0, -1, 0, 0, 0), NewEntity(16, 3.5, 0, 1, 0, 0, 0)}, []Vector2D{NewVector2D(-1, 0), NewVector2D(1, 0)}, 0},
+		// A row of touching balls passes the impulse along to the last one
+		{1, []*Entity{NewEntity(16, 0, 0, 1, 0, 0, 0), NewEntity(16, 3.9, 0, 0, 0, 0, 0), NewEntity(16, 7.8, 0, 0, 0, 0, 0)}, []Vector2D{NewVector2D(0, 0), NewVector2D(0, 0), NewVector2D(1, 0)}, 2},
+	}
+
+	for _, c := range cases {
+		bouncer := &Bouncer{Restitution: c.restitution}
+		survivors := bouncer.Collide(c.entities, 2)
+		if len(survivors) != len(c.entities) || len(bouncer.Records) != c.bounces {
+			t.Errorf("Bouncing %v: got %v after %v bounces - expected %v entities after %v bounces", c.entities, survivors, len(bouncer.Records), len(c.entities), c.bounces)
+			continue
+		}
+		for i, e := range survivors {
+			if utils.RoundPrecision(e.Velocity.X, testprecision) != c.expected[i].X || utils.RoundPrecision(e.Velocity.Y, testprecision) != c.expected[i].Y {
+				t.Errorf("Bouncing %v with restitution %v: entity %v got velocity %v - expected %v", c.entities, c.restitution, i, e.Velocity, c.expected[i])
+			}
+		}
+		for i, e1 := range survivors {
+			for _, e2 := range survivors[i+1:] {
+				if e1.Distance(e2) < e1.Radius+e2.Radius-1e-9 {
+					t.Errorf("Bouncing %v: %v and %v still overlap", c.entities, e1, e2)
+				}
+			}
+		}
+	}
+}
+
+func TestBouncerConservation(t *testing.T) {
+	t.Parallel()
+	entities := []*Entity{NewEntity(9, 0, 0, 3, 1, 0, 0), NewEntity(4, 1.8, 1.2, -2, 0.5, 0, 0), NewEntity(1, 1, -1.2, 0, 2, 0, 0)}
+	momentum := func() Vector2D {
+		total := NewVector2D(0, 0)
+		for _, e := range entities {
+			total = total.Add(e.Velocity.Scalarmul(e.Mass))
+		}
+		return total
+	}
+	center := func() Point {
+		x, y := 0.0, 0.0
+		for _, e := range entities {
+			x += e.Mass * e.Position.X / 14
+			y += e.Mass * e.Position.Y / 14
+		}
+		return NewPoint(x, y)
+	}
+	kinetic := func() float64 {
+		energy := 0.0
+		for _, e := range entities {
+			energy += e.Mass * e.Velocity.Dotproduct(e.Velocity) / 2
+		}
+		return energy
+	}
+	startmomentum, startcenter, startenergy := momentum(), center(), kinetic()
+
+	// Perfectly elastic bounces between several bodies at once keep momentum, center of mass and kinetic energy
+	(&Bouncer{Restitution: 1}).Collide(entities, 0)
+	if momentum().Subtract(startmomentum).Length() > 1e-9 {
+		t.Errorf("Bouncing several bodies: momentum went from %v to %v - expected no change", startmomentum, momentum())
+	}
+	if center().DisplacementVector(startcenter).Length() > 1e-9 {
+		t.Errorf("Bouncing several bodies: center of mass went from %v to %v - expected no change", startcenter, center())
+	}
+	if math.Abs(kinetic()-startenergy) > 1e-9*startenergy {
+		t.Errorf("Bouncing several bodies elastically: kinetic energy went from %v to %v - expected no change", startenergy, kinetic())
+	}
+}
+
+func TestBouncerPairRestitution(t *testing.T) {
+	t.Parallel()
+	sticky := NewEntity(16, 23.5, 0, -1, 0, 0, 0)
+	entities := []*Entity{NewEntity(16, 0, 0, 1, 0, 0, 0), NewEntity(16, 3.5, 0, -1, 0, 0, 0), NewEntity(16, 20, 0, 1, 0, 0, 0), sticky}
+	bouncer := &Bouncer{Restitution: 1, PairRestitution: func(e1 *Entity, e2 *Entity) float64 {
+		if e1 == sticky || e2 == sticky {
+			return 0
+		}
+		return 1
+	}}
+	bouncer.Collide(entities, 0)
+
+	expected := []Vector2D{NewVector2D(-1, 0), NewVector2D(1, 0), NewVector2D(0, 0), NewVector2D(0, 0)}
+	for i, e := range entities {
+		if utils.RoundPrecision(e.Velocity.X, 6) != expected[i].X || utils.RoundPrecision(e.Velocity.Y, 6) != expected[i].Y {
+			t.Errorf("Bouncing with per pair restitution: entity %v got velocity %v - expected %v", i, e.Velocity, expected[i])
+		}
+	}
+}
+
+func TestBouncerMassless(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		e1, e2    *Entity
+		velocity1 Vector2D
+		velocity2 Vector2D
+	}{
+		// A massive entity bounces off a massless one as off a wall, leaving it be
+		{NewEntity(4, -1, 0, 2, 0, 0, 0), NewEntity(0, 1, 0, 0, 0, 0, 0), NewVector2D(-2, 0), NewVector2D(0, 0)},
+		{NewEntity(0, -1, 0, 0, 0, 0, 0), NewEntity(4, 1, 0, -2, 0, 0, 0), NewVector2D(0, 0), NewVector2D(2, 0)},
+		// Two massless entities pass on unchanged
+		{NewEntity(0, -0.1, 0, 2, 0, 0, 0), NewEntity(0, 0.1, 0, -2, 0, 0, 0), NewVector2D(2, 0), NewVector2D(-2, 0)},
+	}
+
+	for _, c := range cases {
+		c.e1.Radius, c.e2.Radius = 1.5, 1.5
+		(&Bouncer{Restitution: 1}).Collide([]*Entity{c.e1, c.e2}, 0)
+		if c.e1.Velocity != c.velocity1 || c.e2.Velocity != c.velocity2 || nonfinitefield(c.e1) != "" || nonfinitefield(c.e2) != "" {
+			t.Errorf("Bouncing massless entities: got %v and %v - expected velocities %v and %v", c.e1, c.e2, c.velocity1, c.velocity2)
+		}
+		if overlapping(c.e1, c.e2, Domain{}) {
+			t.Errorf("Bouncing massless entities: %v and %v still overlap", c.e1, c.e2)
+		}
+	}
+}
+
+func TestCollidersMassless(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		collider Collider
+	}{
+		{"merging", &Merger{}},
+		{"fragmenting", &Fragmenter{Threshold: 0, Fragments: 4, Dispersion: 0.5}},
+	}
+
+	// Massless entities that merge are weighted equally rather than dividing by their total mass
+	for _, c := range cases {
+		e1, e2 := NewEntity(0, -0.5, 0, 2, 0, 0, 0), NewEntity(0, 0.5, 1, -2, 4, 0, 0)
+		e1.Radius, e2.Radius = 1.5, 1.5
+		survivors := c.collider.Collide([]*Entity{e1, e2}, 0)
+		if len(survivors) != 1 || nonfinitefield(survivors[0]) != "" || survivors[0].Position != NewPoint(0, 0.5) || survivors[0].Velocity != NewVector2D(0, 2) {
+			t.Errorf("Colliding massless entities by %v: got %v - expected a single massless body at (0, 0.5) moving at (0, 2)", c.name, survivors)
+		}
+	}
+}
+
+func TestFragmenterCollide(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		speed     float64
+		fragments int
+	}{
+		// Too gentle to shatter, so the pair merges
+		{1, 1},
+		// Just over the threshold
+		{2 * math.Sqrt2, 4},
+		// Twice the specific energy of the threshold doubles the fragments
+		{4, 8},
+		// Capped at the most fragments
+		{40, 10},
+	}
+
+	for _, c := range cases {
+		// Relative speed 2 root 2 between these equal masses gives a specific impact energy of 1
+		entities := []*Entity{NewEntity(16, -1, 0.5, c.speed/2+1, 2, 0, 0), NewEntity(16, 1, 0, -c.speed/2+1, 2, 0, 0), NewEntity(4, 100, 0, 0, 0, 0, 0)}
+		fragmenter := &Fragmenter{Threshold: 0.999, Fragments: 4, MaxFragments: 10, Dispersion: 0.5}
+		survivors := fragmenter.Collide(entities, 3)
+
+		if len(survivors) != 1+c.fragments || len(fragmenter.Records) != 1 || len(fragmenter.Records[0].Fragments) != c.fragments {
+			t.Errorf("Colliding at speed %v: got %v - expected %v fragments and the bystander", c.speed, survivors, c.fragments)
+			continue
+		}
+		if survivors[0] != entities[2] {
+			t.Errorf("Colliding at speed %v: got %v first - expected the bystander %v", c.speed, survivors[0], entities[2])
+		}
+
+		mass, momentum, center, energy, area := 0.0, NewVector2D(0, 0), NewVector2D(0, 0), 0.0, 0.0
+		fragments := fragmenter.Records[0].Fragments
+		for _, e := range fragments {
+			mass += e.Mass
+			area += e.Radius * e.Radius
+			momentum = momentum.Add(e.Velocity.Scalarmul(e.Mass))
+			center = center.Add(NewPoint(0, 0).DisplacementVector(e.Position).Scalarmul(e.Mass / 32))
+		}
+		for _, e := range fragments {
+			relative := e.Velocity.Subtract(momentum.Scalarmul(1 / mass))
+			energy += e.Mass * relative.Dotproduct(relative) / 2
+		}
+
+		if math.Abs(mass-32) > 1e-9 || momentum.Subtract(NewVector2D(32, 64)).Length() > 1e-9 || center.Subtract(NewVector2D(0, 0.25)).Length() > 1e-9 {
+			t.Errorf("Colliding at speed %v: got mass %v, momentum %v, center of mass %v - expected 32, (32, 64), (0, 0.25)", c.speed, mass, momentum, center)
+		}
+
+		// Fragments share out the disk area of the pair
+		if math.Abs(area-8) > 1e-9 {
+			t.Errorf("Colliding at speed %v: fragments have squared radii summing to %v - expected 8", c.speed, area)
+		}
+
+		// Fragments carry the dispersed share of the impact energy, and start apart from each other
+		impact := 4 * c.speed * c.speed
+		if c.fragments > 1 && math.Abs(energy-0.5*impact) > 1e-9*impact {
+			t.Errorf("Colliding at speed %v: fragments have kinetic energy %v - expected %v", c.speed, energy, 0.5*impact)
+		}
+		for i, e1 := range fragments {
+			for _, e2 := range fragments[i+1:] {
+				if overlapping(e1, e2, Domain{}) {
+					t.Errorf("Colliding at speed %v: fragments %v and %v overlap", c.speed, e1, e2)
+				}
+			}
+		}
+	}
+}
+
+func TestFragmenterDeterministic(t *testing.T) {
+	t.Parallel()
+	collide := func() []*Entity {
+		entities := []*Entity{NewEntity(16, -1, 0, 10, 0, 0, 0), NewEntity(9, 1, 0, -10, 0, 0, 0)}
+		return (&Fragmenter{Threshold: 1, Fragments: 3, MaxFragments: 20, Dispersion: 0.8, Random: rand.New(rand.NewSource(42))}).Collide(entities, 0)
+	}
+	if first, second := collide(), collide(); !reflect.DeepEqual(first, second) {
+		t.Errorf("Colliding with the same seed: got %v then %v - expected the same fragments", first, second)
+	}
+}
+
+func TestFragmenterDistributions(t *testing.T) {
+	t.Parallel()
+
+	// Fragments of equal mass thrown out at equal speeds share the dispersed energy evenly
+	entities := []*Entity{NewEntity(16, -1, 0, 10, 0, 0, 0), NewEntity(16, 1, 0, -10, 0, 0, 0)}
+	even := func(random *rand.Rand) float64 { return 1 }
+	fragmenter := &Fragmenter{Threshold: 1, Fragments: 6, MaxFragments: 6, Dispersion: 0.5, MassDistribution: even, SpeedDistribution: even}
+	fragments := fragmenter.Collide(entities, 0)
+	if len(fragments) != 6 {
+		t.Fatalf("Shattering with even distributions: got %v - expected 6 fragments", fragments)
+	}
+	for _, fragment := range fragments {
+		if math.Abs(fragment.Mass-32.0/6) > 1e-9 || math.Abs(fragment.Velocity.Length()-fragments[0].Velocity.Length()) > 1e-9 {
+			t.Errorf("Shattering with even distributions: got %v - expected mass %v at the speed of %v", fragment, 32.0/6, fragments[0])
+		}
+	}
+}
+
+func TestCollidersPeriodic(t *testing.T) {
+	t.Parallel()
+	domain := NewDomain(200, 200)
+	pair := func() []*Entity {
+		return []*Entity{NewEntity(4, 99.5, 10, 1, 0, 0, 0), NewEntity(4, -99.5, 10, -1, 0, 0, 0)}
+	}
+
+	// Entities either side of an edge merge at their center of mass on the edge
+	merged := (&Merger{Domain: domain}).Collide(pair(), 0)
+	if len(merged) != 1 || math.Abs(math.Abs(merged[0].Position.X)-100) > 1e-9 || merged[0].Position.Y != 10 || !domain.Contains(merged[0].Position) {
+		t.Errorf("Merging across an edge: got %v - expected one body on the edge at y 10", merged)
+	}
+
+	// And bounce back away from it
+	bounced := (&Bouncer{Restitution: 1, Domain: domain}).Collide(pair(), 0)
+	if bounced[0].Velocity != NewVector2D(-1, 0) || bounced[1].Velocity != NewVector2D(1, 0) {
+		t.Errorf("Bouncing across an edge: got %v - expected the pair heading back", bounced)
+	}
+
+	// But not without a domain
+	if apart := (&Merger{}).Collide(pair(), 0); len(apart) != 2 {
+		t.Errorf("Merging on an open plane: got %v - expected the pair apart", apart)
+	}
+}
@@ -0,0 +1,153 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Diagnostics holds the quantities a closed system of entities should conserve, along with the center of mass,
+// which should drift at the constant velocity of the total momentum
+type Diagnostics struct {
+	Mass            float64
+	Kinetic         float64
+	Potential       float64
+	Momentum        Vector2D
+	AngularMomentum float64
+	CenterOfMass    Point
+
+	// Sums of the sizes of each entity's contribution, which a drift of a total that is near zero is relative to
+	momentumscale float64
+	angularscale  float64
+}
+
+// Drift holds how far the conserved quantities of a system have moved from a reference state, each relative to
+// the size of the quantity in the reference. Momentum, whose total is often zero, is relative to the summed size of
+// every entity's momentum instead
+type Drift struct {
+	Energy          float64
+	Momentum        float64
+	AngularMomentum float64
+}
+
+// Diagnose returns the diagnostics of the entities, with the potential energy softened by the given softening so
+// it matches the forces of a solver using it
+func Diagnose(entities []*Entity, softening Softening) Diagnostics {
+	return diagnose(entities, softening, false, G)
+}
+
+// DiagnoseCompensated returns the diagnostics of the entities like Diagnose, but sums every total with compensated
+// summation so light entities still count next to much heavier ones
+func DiagnoseCompensated(entities []*Entity, softening Softening) Diagnostics {
+	return diagnose(entities, softening, true, G)
+}
+
+// Diagnose returns the diagnostics of the entities measured in the units, with potential energy from their
+// gravitational constant
+func (u Units) Diagnose(entities []*Entity, softening Softening) Diagnostics {
+	return diagnose(entities, softening, false, u.gravity())
+}
+
+// DiagnoseCompensated returns the diagnostics of the entities measured in the units like Diagnose, summed with
+// compensated summation
+func (u Units) DiagnoseCompensated(entities []*Entity, softening Softening) Diagnostics {
+	return diagnose(entities, softening, true, u.gravity())
+}
+
+// diagnose returns the diagnostics of the entities under the gravitational constant g, summed with compensated
+// summation or not
+func diagnose(entities []*Entity, softening Softening, compensated bool, g float64) Diagnostics {
+	mass, kinetic, potential := Sum{Compensated: compensated}, Sum{Compensated: compensated}, Sum{Compensated: compensated}
+	momentumscale, x, y := Sum{Compensated: compensated}, Sum{Compensated: compensated}, Sum{Compensated: compensated}
+	momentum := NewVectorSum(compensated)
+	for i, e1 := range entities {
+		mass.Add(e1.Mass)
+		kinetic.Add(e1.Mass * e1.Velocity.Dotproduct(e1.Velocity) / 2)
+		momentum.Add(e1.Velocity.Scalarmul(e1.Mass))
+		momentumscale.Add(e1.Mass * e1.Velocity.Length())
+		x.Add(e1.Mass * e1.Position.X)
+		y.Add(e1.Mass * e1.Position.Y)
+		for _, e2 := range entities[i+1:] {
+			potential.Add(g * e1.Mass * e2.Mass * softening.potential(e1.Distance(e2)))
+		}
+	}
+	d := Diagnostics{
+		Mass:          mass.Value(),
+		Kinetic:       kinetic.Value(),
+		Potential:     potential.Value(),
+		Momentum:      momentum.Value(),
+		momentumscale: momentumscale.Value(),
+	}
+	if d.Mass == 0 {
+		return d
+	}
+	d.CenterOfMass = NewPoint(x.Value()/d.Mass, y.Value()/d.Mass)
+
+	// Angular momentum about the barycenter, in the frame moving with it
+	angular, angularscale := Sum{Compensated: compensated}, Sum{Compensated: compensated}
+	velocity := d.Momentum.Scalarmul(1 / d.Mass)
+	for _, e := range entities {
+		r := d.CenterOfMass.DisplacementVector(e.Position)
+		v := e.Velocity.Subtract(velocity)
+		angular.Add(e.Mass * (r.X*v.Y - r.Y*v.X))
+		angularscale.Add(e.Mass * math.Abs(r.X*v.Y-r.Y*v.X))
+	}
+	d.AngularMomentum, d.angularscale = angular.Value(), angularscale.Value()
+	return d
+}
+
+// CenterOfMassFrame moves the entities into the frame of their barycenter, shifting them so it sits at the origin
+// and changing their velocities so their total momentum is zero. It returns the barycenter and its velocity, which
+// were subtracted from every position and velocity
+func CenterOfMassFrame(entities []*Entity) (Point, Vector2D) {
+	d := Diagnose(entities, Softening{})
+	if d.Mass == 0 {
+		return NewPoint(0, 0), NewVector2D(0, 0)
+	}
+
+	velocity := d.Momentum.Scalarmul(1 / d.Mass)
+	offset := NewPoint(0, 0).DisplacementVector(d.CenterOfMass)
+	for _, e := range entities {
+		e.Position = e.Position.Subtract(offset)
+		e.Velocity = e.Velocity.Subtract(velocity)
+	}
+	return d.CenterOfMass, velocity
+}
+
+// Energy returns the total kinetic and potential energy
+func (d Diagnostics) Energy() float64 {
+	return d.Kinetic + d.Potential
+}
+
+// VirialRatio returns twice the kinetic energy over the magnitude of the potential energy, which is 1 for a bound
+// system in equilibrium, below 1 for one collapsing and above 1 for one flying apart
+func (d Diagnostics) VirialRatio() float64 {
+	return 2 * d.Kinetic / math.Abs(d.Potential)
+}
+
+// Drift returns how far the diagnostics have moved from the reference diagnostics
+func (d Diagnostics) Drift(reference Diagnostics) Drift {
+	return Drift{
+		Energy:          relativechange(d.Energy(), reference.Energy(), reference.Kinetic+math.Abs(reference.Potential)),
+		Momentum:        relativechange(d.Momentum.Subtract(reference.Momentum).Length(), 0, reference.momentumscale),
+		AngularMomentum: relativechange(d.AngularMomentum, reference.AngularMomentum, reference.angularscale),
+	}
+}
+
+// String returns the formatted string "Diagnostics{Energy: ..., Momentum: ..., ...}"
+func (d Diagnostics) String() string {
+	return fmt.Sprintf("Diagnostics{Energy: %v, Momentum: %v, AngularMomentum: %v, CenterOfMass: %v, VirialRatio: %v}", d.Energy(), d.Momentum, d.AngularMomentum, d.CenterOfMass, d.VirialRatio())
+}
+
+// relativechange returns the size of the change from reference to value relative to the reference, or relative to
+// scale when the reference is zero, or the plain change when both are
+func relativechange(value float64, reference float64, scale float64) float64 {
+	change := math.Abs(value - reference)
+	switch {
+	case reference != 0:
+		return change / math.Abs(reference)
+	case scale != 0:
+		return change / scale
+	default:
+		return change
+	}
+}
@@ -0,0 +1,127 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"math"
+	"testing"
+)
+
+func TestDiagnose(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entities  []*Entity
+		softening Softening
+		expected  Diagnostics
+		virial    float64
+	}{
+		{[]*Entity{}, Softening{}, Diagnostics{}, math.NaN()},
+		// A pair circling their barycenter, which is in equilibrium
+		{[]*Entity{NewEntity(1, -1, 0, 0, -math.Sqrt(G/4), 0, 0), NewEntity(1, 1, 0, 0, math.Sqrt(G/4), 0, 0)}, Softening{},
+			Diagnostics{Mass: 2, Kinetic: 166.9585, Potential: -333.917, Momentum: NewVector2D(0, 0), AngularMomentum: 25.8425, CenterOfMass: NewPoint(0, 0)}, 1},
+		// A moving barycenter adds momentum but no angular momentum about it
+		{[]*Entity{NewEntity(3, 0, 0, 1, 0, 0, 0), NewEntity(1, 4, 0, 1, 0, 0, 0)}, Softening{Plummer, 3},
+			Diagnostics{Mass: 4, Kinetic: 2, Potential: -400.7004, Momentum: NewVector2D(4, 0), AngularMomentum: 0, CenterOfMass: NewPoint(1, 0)}, 0.01},
+	}
+
+	for _, c := range cases {
+		d := Diagnose(c.entities, c.softening)
+		if d.Mass != c.expected.Mass ||
+			utils.RoundPrecision(d.Kinetic, testprecision) != c.expected.Kinetic ||
+			utils.RoundPrecision(d.Potential, testprecision) != c.expected.Potential ||
+			utils.RoundPrecision(d.AngularMomentum, testprecision) != c.expected.AngularMomentum ||
+			d.Momentum.Subtract(c.expected.Momentum).Length() > 1e-9 ||
+			pointsinequal(d.CenterOfMass, c.expected.CenterOfMass, testprecision) {
+			t.Errorf("Diagnosing %v: got %v - expected %v", c.entities, d, c.expected)
+		}
+		if virial := utils.RoundPrecision(d.VirialRatio(), testprecision); virial != c.virial && !(math.IsNaN(virial) && math.IsNaN(c.virial)) {
+			t.Errorf("Diagnosing %v: got virial ratio %v - expected %v", c.entities, virial, c.virial)
+		}
+	}
+}
+
+func TestDiagnosticsDrift(t *testing.T) {
+	t.Parallel()
+
+	// Long runs conserve energy and momentum to the accuracy of the integrator
+	entities := solarsystem()
+	reference := Diagnose(entities, Softening{})
+	for i := 0; i < 1000; i++ {
+		Yoshida4{}.Step(entities, DirectSolver{}, 0.01)
+	}
+	drift := Diagnose(entities, Softening{}).Drift(reference)
+	if drift.Energy > 1e-8 || drift.Momentum > 1e-12 || drift.AngularMomentum > 1e-10 {
+		t.Errorf("Advancing a solar system: drifted %+v - expected energy, momentum and angular momentum conserved", drift)
+	}
+
+	// Kicking an entity shows up as drift in every quantity
+	entities[1].Velocity = entities[1].Velocity.Scalarmul(1.1)
+	drift = Diagnose(entities, Softening{}).Drift(reference)
+	if drift.Energy < 1e-4 || drift.Momentum < 1e-4 || drift.AngularMomentum < 1e-4 {
+		t.Errorf("Kicking a planet: drifted %+v - expected energy, momentum and angular momentum to change", drift)
+	}
+}
+
+func TestCenterOfMassFrame(t *testing.T) {
+	t.Parallel()
+	testprecision := 6
+	cases := []struct {
+		entities []*Entity
+		center   Point
+		velocity Vector2D
+		expected []*Entity
+	}{
+		{[]*Entity{}, NewPoint(0, 0), NewVector2D(0, 0), []*Entity{}},
+		{[]*Entity{NewEntity(3, 1, 2, 4, 0, 0, 0), NewEntity(1, 5, -2, 0, 8, 0, 0)}, NewPoint(2, 1), NewVector2D(3, 2),
+			[]*Entity{NewEntity(3, -1, 1, 1, -2, 0, 0), NewEntity(1, 3, -3, -3, 6, 0, 0)}},
+		// Massless entities are carried along without moving the barycenter
+		{[]*Entity{NewEntity(2, 10, 10, 1, 1, 0, 0), NewEntity(0, 0, 0, 0, 0, 0, 0)}, NewPoint(10, 10), NewVector2D(1, 1),
+			[]*Entity{NewEntity(2, 0, 0, 0, 0, 0, 0), NewEntity(0, -10, -10, -1, -1, 0, 0)}},
+	}
+
+	for _, c := range cases {
+		center, velocity := CenterOfMassFrame(c.entities)
+		if pointsinequal(center, c.center, testprecision) || utils.RoundPrecision(velocity.X, testprecision) != c.velocity.X || utils.RoundPrecision(velocity.Y, testprecision) != c.velocity.Y {
+			t.Errorf("Moving %v to its center of mass frame: subtracted %v and %v - expected %v and %v", c.entities, center, velocity, c.center, c.velocity)
+		}
+		for i, e := range c.entities {
+			if pointsinequal(e.Position, c.expected[i].Position, testprecision) ||
+				utils.RoundPrecision(e.Velocity.X, testprecision) != c.expected[i].Velocity.X ||
+				utils.RoundPrecision(e.Velocity.Y, testprecision) != c.expected[i].Velocity.Y {
+				t.Errorf("Moving entities to their center of mass frame: got %v - expected %v", e, c.expected[i])
+			}
+		}
+
+		d := Diagnose(c.entities, Softening{})
+		if d.Momentum.Length() > 1e-12 || NewPoint(0, 0).Distance(d.CenterOfMass) > 1e-12 {
+			t.Errorf("Moving entities to their center of mass frame: got %v - expected no momentum about the origin", d)
+		}
+	}
+}
+
+func TestDiagnoseCompensated(t *testing.T) {
+	t.Parallel()
+
+	// A star so heavy that each planet's mass and momentum is below half a unit in the last place of the totals
+	entities := []*Entity{NewEntity(1e16, 0, 0, 1, 0, 0, 0)}
+	for i := 0; i < 1000; i++ {
+		entities = append(entities, NewEntity(0.5, 100, float64(i), 1, 0, 0, 0))
+	}
+	mass, momentum := 1e16+500, 1e16+500
+
+	plain := Diagnose(entities, Softening{})
+	if plain.Mass != 1e16 || plain.Momentum.X != 1e16 {
+		t.Errorf("Diagnosing a heavy star: got mass %v and momentum %v - expected the planets lost to rounding", plain.Mass, plain.Momentum)
+	}
+	compensated := DiagnoseCompensated(entities, Softening{})
+	if compensated.Mass != mass || compensated.Momentum.X != momentum {
+		t.Errorf("Diagnosing a heavy star with compensation: got mass %v and momentum %v - expected %v and %v", compensated.Mass, compensated.Momentum, mass, momentum)
+	}
+
+	// Both agree for ordinary systems
+	entities = solarsystem()
+	plain, compensated = Diagnose(entities, Softening{}), DiagnoseCompensated(entities, Softening{})
+	if drift := compensated.Drift(plain); drift.Energy > 1e-14 || drift.Momentum > 1e-14 || drift.AngularMomentum > 1e-14 {
+		t.Errorf("Diagnosing a solar system with compensation: drifted %+v from plain sums", drift)
+	}
+}
@@ -0,0 +1,77 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Domain is an axis aligned rectangle of the simulation plane
+type Domain struct {
+	MinX float64
+	MinY float64
+	MaxX float64
+	MaxY float64
+}
+
+// NewDomain returns a new Domain of the given width and height centered on the origin
+func NewDomain(width float64, height float64) Domain {
+	return Domain{MinX: -width / 2, MinY: -height / 2, MaxX: width / 2, MaxY: height / 2}
+}
+
+// Width returns the horizontal extent of the domain
+func (d Domain) Width() float64 {
+	return d.MaxX - d.MinX
+}
+
+// Height returns the vertical extent of the domain
+func (d Domain) Height() float64 {
+	return d.MaxY - d.MinY
+}
+
+// Contains reports whether the point lies within the domain, edges included
+func (d Domain) Contains(p Point) bool {
+	return p.X >= d.MinX && p.X <= d.MaxX && p.Y >= d.MinY && p.Y <= d.MaxY
+}
+
+// Wrap returns the point moved by whole widths and heights of the domain so it lies within it, as if the
+// domain tiled the plane
+func (d Domain) Wrap(p Point) Point {
+	return NewPoint(wrap(p.X, d.MinX, d.Width()), wrap(p.Y, d.MinY, d.Height()))
+}
+
+// MinimumImage returns the shortest displacement equivalent to the given one when the domain tiles the plane, so
+// no component is longer than half the domain
+func (d Domain) MinimumImage(v Vector2D) Vector2D {
+	return NewVector2D(v.X-d.Width()*math.Round(v.X/d.Width()), v.Y-d.Height()*math.Round(v.Y/d.Height()))
+}
+
+// separation returns the displacement from p1 to p2, or to the nearest image of p2 when the domain is set and so
+// tiles the plane
+func (d Domain) separation(p1 Point, p2 Point) Vector2D {
+	displacement := p1.DisplacementVector(p2)
+	if d.periodic() {
+		return d.MinimumImage(displacement)
+	}
+	return displacement
+}
+
+// periodic reports whether the domain has an area to tile the plane with, unlike the zero Domain
+func (d Domain) periodic() bool {
+	return d.Width() > 0 && d.Height() > 0
+}
+
+// String returns the formatted string "Domain{MinX: ..., MinY: ..., MaxX: ..., MaxY: ...}"
+func (d Domain) String() string {
+	return fmt.Sprintf("Domain{MinX: %v, MinY: %v, MaxX: %v, MaxY: %v}", d.MinX, d.MinY, d.MaxX, d.MaxY)
+}
+
+// wrap returns x moved by whole periods into [min, min + period)
+func wrap(x float64, min float64, period float64) float64 {
+	wrapped := x - period*math.Floor((x-min)/period)
+
+	// Rounding can land exactly on the far edge, which belongs to the next period
+	if wrapped >= min+period {
+		wrapped -= period
+	}
+	return wrapped
+}
@@ -0,0 +1,70 @@
+package physics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDomainWrap(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		domain   Domain
+		point    Point
+		expected Point
+	}{
+		{NewDomain(10, 10), NewPoint(0, 0), NewPoint(0, 0)},
+		{NewDomain(10, 10), NewPoint(6, -6), NewPoint(-4, 4)},
+		{NewDomain(10, 10), NewPoint(5, -5), NewPoint(-5, -5)},
+		{NewDomain(10, 20), NewPoint(-27, 31), NewPoint(3, -9)},
+		{Domain{0, 0, 4, 2}, NewPoint(9, -1), NewPoint(1, 1)},
+	}
+
+	for _, c := range cases {
+		wrapped := c.domain.Wrap(c.point)
+		if !reflect.DeepEqual(wrapped, c.expected) {
+			t.Errorf("Wrapping %v into %v = %v - expected %v", c.point, c.domain, wrapped, c.expected)
+		}
+	}
+}
+
+func TestDomainContains(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		domain   Domain
+		point    Point
+		expected bool
+	}{
+		{NewDomain(10, 10), NewPoint(0, 0), true},
+		{NewDomain(10, 10), NewPoint(5, -5), true},
+		{NewDomain(10, 10), NewPoint(5.1, 0), false},
+		{Domain{0, 0, 4, 2}, NewPoint(1, -1), false},
+	}
+
+	for _, c := range cases {
+		contains := c.domain.Contains(c.point)
+		if contains != c.expected {
+			t.Errorf("Checking %v contains %v = %v - expected %v", c.domain, c.point, contains, c.expected)
+		}
+	}
+}
+
+func TestDomainMinimumImage(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		domain   Domain
+		vector   Vector2D
+		expected Vector2D
+	}{
+		{NewDomain(10, 10), NewVector2D(3, -4), NewVector2D(3, -4)},
+		{NewDomain(10, 10), NewVector2D(8, -7), NewVector2D(-2, 3)},
+		{NewDomain(10, 20), NewVector2D(-27, 31), NewVector2D(3, -9)},
+		{Domain{0, 0, 4, 2}, NewVector2D(9, -1.5), NewVector2D(1, 0.5)},
+	}
+
+	for _, c := range cases {
+		image := c.domain.MinimumImage(c.vector)
+		if !reflect.DeepEqual(image, c.expected) {
+			t.Errorf("Finding the minimum image of %v in %v = %v - expected %v", c.vector, c.domain, image, c.expected)
+		}
+	}
+}
@@ -7,22 +7,29 @@ import (
 
 type Entity struct {
 	Mass         float64
-	Position     *Point
-	Velocity     *Vector2D
-	Acceleration *Vector2D
+	Radius       float64
+	Position     Point
+	Velocity     Vector2D
+	Acceleration Vector2D
+	Jerk         Vector2D
 }
 
 // Made up gravity so reactions on the seconds scale at small range are fun to watch
 const G float64 = 6.67834E2
 
-// NewEntity returns a new Entity struct from the provided float values
+// NewEntity returns a new Entity struct from the provided float values, with a radius whose disk area grows with mass
 func NewEntity(mass float64, posx float64, posy float64, velx float64, vely float64, accelx float64, accely float64) *Entity {
-	return &Entity{Mass: mass, Position: NewPoint(posx, posy), Velocity: NewVector2D(velx, vely), Acceleration: NewVector2D(accelx, accely)}
+	return &Entity{Mass: mass, Radius: math.Sqrt(mass) / 2, Position: NewPoint(posx, posy), Velocity: NewVector2D(velx, vely), Acceleration: NewVector2D(accelx, accely), Jerk: NewVector2D(0, 0)}
 }
 
-// GravitationalForce returns the gravitational force between two entites based on both entities masses and distance
+// GravitationalForce returns the gravitational force between two entites based on both entities masses and distance,
+// coincident entities exert no force upon each other
 func (e1 *Entity) GravitationalForce(e2 *Entity) float64 {
-	return (G * e1.Mass * e2.Mass) / math.Pow(e1.Distance(e2), 2)
+	distance := e1.Distance(e2)
+	if distance == 0 {
+		return 0
+	}
+	return (G * e1.Mass * e2.Mass) / math.Pow(distance, 2)
 }
 
 // Update updates the position and velocity of the Entity for a given time tick
@@ -33,9 +40,55 @@ func (e1 *Entity) Update(time float64) {
 
 // UpdateGravity updates the acceleration of the Entity based on the aggregate gravitational acceleration of the given entities slice upon the entitiy
 func (e1 *Entity) UpdateGravitationalAcceleration(entities []*Entity) {
+	e1.UpdateSoftenedGravitationalAcceleration(entities, Softening{})
+}
+
+// UpdateSoftenedGravitationalAcceleration updates the acceleration of the Entity based on the aggregate gravitational
+// acceleration of the given entities slice upon the entity, softened at short range by the given softening
+func (e1 *Entity) UpdateSoftenedGravitationalAcceleration(entities []*Entity, softening Softening) {
+	e1.accumulateGravitationalAcceleration(entities, softening, false)
+}
+
+// UpdateCompensatedGravitationalAcceleration updates the acceleration of the Entity like
+// UpdateSoftenedGravitationalAcceleration, but sums the contributions of the given entities with compensated
+// summation so the pull of light entities is not rounded away next to that of much heavier ones
+func (e1 *Entity) UpdateCompensatedGravitationalAcceleration(entities []*Entity, softening Softening) {
+	e1.accumulateGravitationalAcceleration(entities, softening, true)
+}
+
+// accumulateGravitationalAcceleration sets the acceleration of the Entity to the softened pull of the given entities,
+// summed with compensated summation or not
+func (e1 *Entity) accumulateGravitationalAcceleration(entities []*Entity, softening Softening, compensated bool) {
+	acceleration := NewVectorSum(compensated)
+
+	for _, e2 := range entities {
+
+		// Entities should exert no gravity upon themselves - skip
+		if e1 == e2 {
+			continue
+		}
+
+		// Scale the displacement from e1 to e2 rather than normalizing it so coincident entities contribute nothing
+		displacement := e1.Position.DisplacementVector(e2.Position)
+		g, _ := softening.factor(displacement.Length())
+		acceleration.Add(displacement.Scalarmul(G * e2.Mass * g))
+	}
+	e1.Acceleration = acceleration.Value()
+}
+
+// UpdateGravitationalAccelerationAndJerk updates both the acceleration of the Entity and its jerk, the time
+// derivative of acceleration, based on the positions and relative velocities of the given entities slice
+func (e1 *Entity) UpdateGravitationalAccelerationAndJerk(entities []*Entity) {
+	e1.UpdateSoftenedGravitationalAccelerationAndJerk(entities, Softening{})
+}
+
+// UpdateSoftenedGravitationalAccelerationAndJerk updates both the acceleration of the Entity and its jerk based on
+// the positions and relative velocities of the given entities slice, softened at short range by the given softening
+func (e1 *Entity) UpdateSoftenedGravitationalAccelerationAndJerk(entities []*Entity, softening Softening) {
 
-	// Reset acceleration to 0-vector
+	// Reset acceleration and jerk to 0-vectors
 	e1.Acceleration = NewVector2D(0, 0)
+	e1.Jerk = NewVector2D(0, 0)
 
 	for _, e2 := range entities {
 
@@ -44,11 +97,13 @@ func (e1 *Entity) UpdateGravitationalAcceleration(entities []*Entity) {
 			continue
 		}
 
-		// Using gravitational force find the gravitational acceleration vector from e1 to e1
-		gforce := e1.GravitationalForce(e2)
-		gaccelscalar := gforce / e1.Mass
-		normal := e1.Position.DisplacementVector(e2.Position).Normalize()
-		e1.Acceleration = e1.Acceleration.Add(normal.Scalarmul(gaccelscalar))
+		// Differentiating G * m2 * g(|r|) * r with respect to time gives G * m2 * (g * v + g' / |r| * (r . v) * r)
+		displacement := e1.Position.DisplacementVector(e2.Position)
+		relvelocity := e2.Velocity.Subtract(e1.Velocity)
+		g, rate := softening.factor(displacement.Length())
+		gm := G * e2.Mass
+		e1.Acceleration = e1.Acceleration.Add(displacement.Scalarmul(gm * g))
+		e1.Jerk = e1.Jerk.Add(relvelocity.Scalarmul(gm * g)).Add(displacement.Scalarmul(gm * rate * displacement.Dotproduct(relvelocity)))
 	}
 }
 
@@ -0,0 +1,65 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Entity3D is an Entity free to move in three dimensions
+type Entity3D struct {
+	Mass         float64
+	Radius       float64
+	Position     Point3D
+	Velocity     Vector3D
+	Acceleration Vector3D
+}
+
+// NewEntity3D returns a new Entity3D struct from the provided float values, with the same radius as an Entity of
+// the same mass
+func NewEntity3D(mass float64, posx float64, posy float64, posz float64, velx float64, vely float64, velz float64, accelx float64, accely float64, accelz float64) *Entity3D {
+	return &Entity3D{Mass: mass, Radius: math.Sqrt(mass) / 2, Position: NewPoint3D(posx, posy, posz), Velocity: NewVector3D(velx, vely, velz), Acceleration: NewVector3D(accelx, accely, accelz)}
+}
+
+// Update updates the position and velocity of the Entity3D for a given time tick
+func (e1 *Entity3D) Update(time float64) {
+	e1.Position = e1.Position.Add(e1.Velocity.Scalarmul(time))
+	e1.Velocity = e1.Velocity.Add(e1.Acceleration.Scalarmul(time))
+}
+
+// UpdateGravitationalAcceleration updates the acceleration of the Entity3D based on the aggregate gravitational
+// acceleration of the given entities slice upon the entity
+func (e1 *Entity3D) UpdateGravitationalAcceleration(entities []*Entity3D) {
+	e1.UpdateSoftenedGravitationalAcceleration(entities, Softening{})
+}
+
+// UpdateSoftenedGravitationalAcceleration updates the acceleration of the Entity3D based on the aggregate
+// gravitational acceleration of the given entities slice upon the entity, softened at short range by the given
+// softening
+func (e1 *Entity3D) UpdateSoftenedGravitationalAcceleration(entities []*Entity3D, softening Softening) {
+
+	// Reset acceleration to 0-vector
+	e1.Acceleration = NewVector3D(0, 0, 0)
+
+	for _, e2 := range entities {
+
+		// Entities should exert no gravity upon themselves - skip
+		if e1 == e2 {
+			continue
+		}
+
+		// Scale the displacement from e1 to e2 rather than normalizing it so coincident entities contribute nothing
+		displacement := e1.Position.DisplacementVector(e2.Position)
+		g, _ := softening.factor(displacement.Length())
+		e1.Acceleration = e1.Acceleration.Add(displacement.Scalarmul(G * e2.Mass * g))
+	}
+}
+
+// Distance returns the positional distance between two entities
+func (e1 *Entity3D) Distance(e2 *Entity3D) float64 {
+	return e1.Position.Distance(e2.Position)
+}
+
+// String returns the formatted string "Entity3D{Mass: ..., Position: ..., Velocity: ..., Acceleration: ...}"
+func (e *Entity3D) String() string {
+	return fmt.Sprintf("Entity3D{Mass: %v, Position: %v, Velocity: %v, Acceleration: %v}", e.Mass, e.Position, e.Velocity, e.Acceleration)
+}
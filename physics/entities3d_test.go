@@ -0,0 +1,52 @@
+package physics
+
+import (
+	"testing"
+)
+
+// planar returns entities like the given planar entities, lying in the xy plane
+func planar(entities []*Entity) []*Entity3D {
+	entities3d := make([]*Entity3D, len(entities))
+	for n, e := range entities {
+		entities3d[n] = NewEntity3D(e.Mass, e.Position.X, e.Position.Y, 0, e.Velocity.X, e.Velocity.Y, 0, e.Acceleration.X, e.Acceleration.Y, 0)
+	}
+	return entities3d
+}
+
+func TestEntity3DUpdateGravitationalAcceleration(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		entity   *Entity3D
+		entities []*Entity3D
+		expected Vector3D
+	}{
+		{NewEntity3D(1, 0, 0, 0, 0, 0, 0, 0, 0, 0), []*Entity3D{NewEntity3D(1e12, 0, 0, 1, 0, 0, 0, 0, 0, 0)}, NewVector3D(0, 0, 6.67834e14)},
+		{NewEntity3D(1, 0, 0, 0, 0, 0, 0, 0, 0, 0), []*Entity3D{NewEntity3D(1e12, 0, 0, 1, 0, 0, 0, 0, 0, 0), NewEntity3D(1e12, 0, 0, -1, 0, 0, 0, 0, 0, 0)}, NewVector3D(0, 0, 0)},
+		{NewEntity3D(1, 0, 0, 0, 0, 0, 0, 0, 0, 0), []*Entity3D{NewEntity3D(1e12, 0, 0, 0, 0, 0, 0, 0, 0, 0), NewEntity3D(1e12, 0, -2, 0, 0, 0, 0, 0, 0, 0)}, NewVector3D(0, -1.669585e14, 0)},
+		{NewEntity3D(1, 1, 1, 1, 0, 0, 0, 5, 5, 5), []*Entity3D{NewEntity3D(1e12, 3, 4, 7, 0, 0, 0, 0, 0, 0)}, NewVector3D(2, 3, 6).Scalarmul(6.67834e14 / 343)},
+	}
+
+	for _, c := range cases {
+		c.entity.UpdateGravitationalAcceleration(c.entities)
+		if c.entity.Acceleration.Subtract(c.expected).Length() > 1e-12*6.67834e14 {
+			t.Errorf("Computing update: got %v - expected %v", c.entity.Acceleration, c.expected)
+		}
+	}
+}
+
+func TestEntity3DMatchesPlanarEntity(t *testing.T) {
+	t.Parallel()
+	softenings := []Softening{{}, {Kernel: Plummer, Length: 5}, {Kernel: Spline, Length: 20}}
+	for _, softening := range softenings {
+		entities := randomentities(32, 100, 7)
+		entities3d := planar(entities)
+		for n := range entities {
+			entities[n].UpdateSoftenedGravitationalAcceleration(entities, softening)
+			entities3d[n].UpdateSoftenedGravitationalAcceleration(entities3d, softening)
+			a, a3d := entities[n].Acceleration, entities3d[n].Acceleration
+			if a3d != NewVector3D(a.X, a.Y, 0) {
+				t.Errorf("Computing %v planar acceleration got %v - expected %v", softening, a3d, a)
+			}
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package physics
 
 import (
+	"fmt"
 	"github.com/tkajder/gravitysimulator/utils"
 	"reflect"
 	"testing"
@@ -18,6 +19,7 @@ func TestEntityGravitationalForce(t *testing.T) {
 		{NewEntity(2, 0, 0, 0, 0, 0, 0), NewEntity(2, 2, 0, 0, 0, 0, 0), 667.834},
 		{NewEntity(3, 0, 0, 0, 0, 0, 0), NewEntity(6, 5, 0, 0, 0, 0, 0), 480.840},
 		{NewEntity(4.2, 0, 0, 0, 0, 0, 0), NewEntity(13.13, 6.841, 0, 0, 0, 0, 0), 786.943},
+		{NewEntity(1, 3, 4, 0, 0, 0, 0), NewEntity(1, 3, 4, 0, 0, 0, 0), 0},
 	}
 
 	for _, c := range cases {
@@ -61,6 +63,8 @@ func TestEntityUpdateGravitationalAcceleration(t *testing.T) {
 		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1E12, 1, 0, 0, 0, 0, 0)}, NewEntity(1, 0, 0, 0, 0, 6.67834E14, 0)},
 		{NewEntity(1e20, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1e20, 1, 0, 0, 0, 0, 0), NewEntity(1e20, -1, 0, 0, 0, 0, 0)}, NewEntity(1e20, 0, 0, 0, 0, 0, 0)},
 		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1e12, 1, 0, 0, 0, 0, 0), NewEntity(2e12, 0, 1, 0, 0, 0, 0), NewEntity(1e12, -1, 0, 0, 0, 0, 0)}, NewEntity(1, 0, 0, 0, 0, 0, 2*6.67834E14)},
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1e12, 0, 0, 0, 0, 0, 0), NewEntity(1e12, 1, 0, 0, 0, 0, 0)}, NewEntity(1, 0, 0, 0, 0, 6.67834e14, 0)},
+		{NewEntity(0, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1e12, 1, 0, 0, 0, 0, 0)}, NewEntity(0, 0, 0, 0, 0, 6.67834e14, 0)},
 	}
 
 	for _, c := range cases {
@@ -71,6 +75,56 @@ func TestEntityUpdateGravitationalAcceleration(t *testing.T) {
 	}
 }
 
+func TestEntityUpdateGravitationalAccelerationAndJerk(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entity   *Entity
+		entities []*Entity
+		expected *Entity
+		jerk     Vector2D
+	}{
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1e12, 1, 0, 0, 0, 0, 0)}, NewEntity(1, 0, 0, 0, 0, 6.67834e14, 0), NewVector2D(0, 0)},
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1e12, 1, 0, 0, 1, 0, 0)}, NewEntity(1, 0, 0, 0, 0, 6.67834e14, 0), NewVector2D(0, 6.67834e14)},
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1e12, 1, 0, 1, 0, 0, 0)}, NewEntity(1, 0, 0, 0, 0, 6.67834e14, 0), NewVector2D(-2*6.67834e14, 0)},
+		{NewEntity(1, 0, 0, 1, 1, 0, 0), []*Entity{NewEntity(1e12, 1, 0, 1, 1, 0, 0), NewEntity(1e12, -1, 0, 1, 1, 0, 0)}, NewEntity(1, 0, 0, 0, 0, 0, 0), NewVector2D(0, 0)},
+	}
+
+	for _, c := range cases {
+		c.entity.UpdateGravitationalAccelerationAndJerk(c.entities)
+		if accelerationsinequal(c.entity, c.expected, testprecision) {
+			t.Errorf("Computing acceleration and jerk: got %v - expected %v", c.entity, c.expected)
+		}
+		if utils.RoundPrecision(c.entity.Jerk.X, testprecision) != c.jerk.X || utils.RoundPrecision(c.entity.Jerk.Y, testprecision) != c.jerk.Y {
+			t.Errorf("Computing acceleration and jerk: got jerk %v - expected %v", c.entity.Jerk, c.jerk)
+		}
+	}
+}
+
+func TestEntityUpdateSoftenedGravitationalAcceleration(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entity    *Entity
+		entities  []*Entity
+		softening Softening
+		expected  *Entity
+	}{
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1, 1, 0, 0, 0, 0, 0)}, Softening{}, NewEntity(1, 0, 0, 0, 0, 667.834, 0)},
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1, 1, 0, 0, 0, 0, 0)}, Softening{Plummer, 1}, NewEntity(1, 0, 0, 0, 0, 236.115, 0)},
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1, 2, 0, 0, 0, 0, 0)}, Softening{Spline, 1}, NewEntity(1, 0, 0, 0, 0, 166.9585, 0)},
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0)}, Softening{Plummer, 1}, NewEntity(1, 0, 0, 0, 0, 0, 0)},
+		{NewEntity(1, 0, 0, 0, 0, 0, 0), []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0)}, Softening{Spline, 1}, NewEntity(1, 0, 0, 0, 0, 0, 0)},
+	}
+
+	for _, c := range cases {
+		c.entity.UpdateSoftenedGravitationalAcceleration(c.entities, c.softening)
+		if accelerationsinequal(c.entity, c.expected, testprecision) {
+			t.Errorf("Computing softened update with %v: got %v - expected %v", c.softening, c.entity, c.expected)
+		}
+	}
+}
+
 func accelerationsinequal(e1 *Entity, e2 *Entity, testprecision int) bool {
 	xinequal := utils.RoundPrecision(e1.Acceleration.X, testprecision) != e2.Acceleration.X
 	yinequal := utils.RoundPrecision(e1.Acceleration.Y, testprecision) != e2.Acceleration.Y
@@ -98,3 +152,34 @@ func TestEntityDistance(t *testing.T) {
 		}
 	}
 }
+
+func TestEntityUpdateGravitationalAccelerationAllocations(t *testing.T) {
+	entities := randomentities(50, 300, 7)
+	cases := []struct {
+		name   string
+		update func()
+	}{
+		{"acceleration", func() { entities[0].UpdateGravitationalAcceleration(entities) }},
+		{"acceleration and jerk", func() { entities[0].UpdateGravitationalAccelerationAndJerk(entities) }},
+		{"softened acceleration", func() { entities[0].UpdateSoftenedGravitationalAcceleration(entities, Softening{Spline, 5}) }},
+		{"direct solver", func() { DirectSolver{}.Accelerate(entities) }},
+	}
+
+	for _, c := range cases {
+		if allocs := testing.AllocsPerRun(10, c.update); allocs != 0 {
+			t.Errorf("Computing %v of %v entities: %v allocations - expected none", c.name, len(entities), allocs)
+		}
+	}
+}
+
+func BenchmarkEntityUpdateGravitationalAcceleration(b *testing.B) {
+	for _, count := range []int{10, 100, 1000} {
+		entities := randomentities(count, 300, 3)
+		b.Run(fmt.Sprint(count), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				entities[i%count].UpdateGravitationalAcceleration(entities)
+			}
+		})
+	}
+}
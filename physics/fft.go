@@ -0,0 +1,66 @@
+package physics
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fft transforms the values in place with the iterative radix-2 Cooley-Tukey algorithm, the length must be a
+// power of two. The inverse transform is scaled by 1/n so the two round trip
+func fft(values []complex128, inverse bool) {
+	n := len(values)
+
+	// Reorder into bit reversed index order
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			values[i], values[j] = values[j], values[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			twiddle := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := values[start+k], twiddle*values[start+k+size/2]
+				values[start+k] = even + odd
+				values[start+k+size/2] = even - odd
+				twiddle *= step
+			}
+		}
+	}
+
+	if inverse {
+		scale := complex(1/float64(n), 0)
+		for i := range values {
+			values[i] *= scale
+		}
+	}
+}
+
+// fft2 transforms a square grid flattened row by row in place, the side must be a power of two
+func fft2(grid []complex128, side int, inverse bool) {
+	for row := 0; row < side; row++ {
+		fft(grid[row*side:(row+1)*side], inverse)
+	}
+
+	column := make([]complex128, side)
+	for col := 0; col < side; col++ {
+		for row := 0; row < side; row++ {
+			column[row] = grid[row*side+col]
+		}
+		fft(column, inverse)
+		for row := 0; row < side; row++ {
+			grid[row*side+col] = column[row]
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"testing"
+)
+
+func TestFFT(t *testing.T) {
+	t.Parallel()
+	testprecision := 9
+	cases := []struct {
+		values   []complex128
+		expected []complex128
+	}{
+		{[]complex128{1}, []complex128{1}},
+		{[]complex128{1, 0, 0, 0}, []complex128{1, 1, 1, 1}},
+		{[]complex128{1, 1, 1, 1}, []complex128{4, 0, 0, 0}},
+		{[]complex128{0, 1, 0, 0}, []complex128{1, -1i, -1, 1i}},
+		{[]complex128{1, 2, 3, 4, 5, 6, 7, 8}, []complex128{36, -4 + 9.656854249i, -4 + 4i, -4 + 1.656854249i, -4, -4 - 1.656854249i, -4 - 4i, -4 - 9.656854249i}},
+	}
+
+	for _, c := range cases {
+		transformed := append([]complex128{}, c.values...)
+		fft(transformed, false)
+		if complexesinequal(transformed, c.expected, testprecision) {
+			t.Errorf("Transforming %v = %v - expected %v", c.values, transformed, c.expected)
+		}
+
+		fft(transformed, true)
+		if complexesinequal(transformed, c.values, testprecision) {
+			t.Errorf("Inverse transforming back to %v = %v", c.values, transformed)
+		}
+	}
+}
+
+func TestFFT2(t *testing.T) {
+	t.Parallel()
+	testprecision := 9
+	cases := []struct {
+		grid     []complex128
+		side     int
+		expected []complex128
+	}{
+		{[]complex128{1, 0, 0, 0}, 2, []complex128{1, 1, 1, 1}},
+		{[]complex128{1, 2, 3, 4}, 2, []complex128{10, -2, -4, 0}},
+	}
+
+	for _, c := range cases {
+		transformed := append([]complex128{}, c.grid...)
+		fft2(transformed, c.side, false)
+		if complexesinequal(transformed, c.expected, testprecision) {
+			t.Errorf("Transforming %v = %v - expected %v", c.grid, transformed, c.expected)
+		}
+
+		fft2(transformed, c.side, true)
+		if complexesinequal(transformed, c.grid, testprecision) {
+			t.Errorf("Inverse transforming back to %v = %v", c.grid, transformed)
+		}
+	}
+}
+
+func complexesinequal(values []complex128, expected []complex128, testprecision int) bool {
+	for i := range values {
+		if utils.RoundPrecision(real(values[i]), testprecision) != real(expected[i]) || utils.RoundPrecision(imag(values[i]), testprecision) != imag(expected[i]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,338 @@
+package physics
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Defaults used when an FMMSolver leaves Order or LeafSize unset
+const (
+	defaultfmmorder    int = 8
+	defaultfmmleafsize int = 16
+)
+
+// FMMSolver computes the gravitational acceleration of every entity with the
+// fast multipole method on a uniform quadtree, in time linear in the number of
+// entities. Positions are complex numbers z, and the potential of a mass at w,
+// 1 / |z - w|, is the product of (z - w)^(-1/2) and its conjugate. Expanding
+// both factors as power series gives multipole and local expansions in z and
+// its conjugate truncated at Order terms each. Entities in neighbouring leaves
+// interact directly, softened by Softening, everything further away interacts
+// through the expansions unsoftened, so Softening should be well below the
+// width of a leaf
+type FMMSolver struct {
+	Order     int
+	LeafSize  int
+	Softening Softening
+}
+
+// expansion holds the coefficients c[k][l] of z^k conj(z)^l, flattened to k * (order + 1) + l
+type expansion []complex128
+
+// fmmbox is a cell of the uniform quadtree with its multipole and local expansions about its center
+type fmmbox struct {
+	center    complex128
+	mass      float64
+	multipole expansion
+	local     expansion
+	entities  []*Entity
+}
+
+// Accelerate updates the acceleration of every entity with the fast multipole method
+func (s FMMSolver) Accelerate(entities []*Entity) {
+	if len(entities) == 0 {
+		return
+	}
+	order, leafsize := s.Order, s.LeafSize
+	if order <= 0 {
+		order = defaultfmmorder
+	}
+	if leafsize <= 0 {
+		leafsize = defaultfmmleafsize
+	}
+	terms := order + 1
+
+	// Enough levels that leaves hold about leafsize entities, with at least two so some boxes are well separated
+	depth := 2
+	for len(entities) > leafsize<<(2*uint(depth)) {
+		depth++
+	}
+	levels := buildfmmtree(entities, depth, terms)
+	leaves := levels[depth]
+	side := 1 << uint(depth)
+
+	// Upward pass: multipoles of leaves from their entities, then of parents from their children
+	for _, box := range leaves {
+		for _, e := range box.entities {
+			d := complex(e.Position.X, e.Position.Y) - box.center
+			dbar := cmplx.Conj(d)
+			dk := complex(e.Mass, 0)
+			for k := 0; k < terms; k++ {
+				dkl := dk
+				for l := 0; l < terms; l++ {
+					box.multipole[k*terms+l] += dkl
+					dkl *= dbar
+				}
+				dk *= d
+			}
+			box.mass += e.Mass
+		}
+	}
+	for level := depth; level > 0; level-- {
+		width := 1 << uint(level)
+		for index, box := range levels[level] {
+			if box.mass == 0 {
+				continue
+			}
+			parent := levels[level-1][(index/width/2)*(width/2)+(index%width)/2]
+			parent.mass += box.mass
+			parent.multipole.add(box.multipole.shiftmultipole(box.center-parent.center, terms))
+		}
+	}
+
+	// Downward pass: locals from the multipoles of well separated boxes whose parents neighbour the parent, plus the parent's local
+	coefficients := fmmcoefficients(terms)
+	for level := 2; level <= depth; level++ {
+		width := 1 << uint(level)
+		for index, box := range levels[level] {
+			if box.mass == 0 {
+				continue
+			}
+			ix, iy := index%width, index/width
+			if level > 2 {
+				parent := levels[level-1][(iy/2)*(width/2)+ix/2]
+				box.local.add(parent.local.shiftlocal(box.center-parent.center, terms))
+			}
+			for jy := 2*(iy/2) - 2; jy < 2*(iy/2)+4; jy++ {
+				for jx := 2*(ix/2) - 2; jx < 2*(ix/2)+4; jx++ {
+					if jx < 0 || jy < 0 || jx >= width || jy >= width || (absint(jx-ix) <= 1 && absint(jy-iy) <= 1) {
+						continue
+					}
+					source := levels[level][jy*width+jx]
+					if source.mass == 0 {
+						continue
+					}
+					box.local.add(source.multipole.tolocal(box.center-source.center, terms, coefficients))
+				}
+			}
+		}
+	}
+
+	// Leaves: evaluate the local expansion gradient at each entity and sum neighbouring leaves directly
+	for index, box := range leaves {
+		ix, iy := index%side, index/side
+		for _, e := range box.entities {
+			t := complex(e.Position.X, e.Position.Y) - box.center
+			gradient := box.local.gradient(t, terms)
+			ax, ay := G*real(gradient), G*imag(gradient)
+			for jy := iy - 1; jy <= iy+1; jy++ {
+				for jx := ix - 1; jx <= ix+1; jx++ {
+					if jx < 0 || jy < 0 || jx >= side || jy >= side {
+						continue
+					}
+					for _, other := range leaves[jy*side+jx].entities {
+						if other == e {
+							continue
+						}
+						dx, dy := other.Position.X-e.Position.X, other.Position.Y-e.Position.Y
+						g, _ := s.Softening.factor(math.Sqrt(dx*dx + dy*dy))
+						ax += G * other.Mass * g * dx
+						ay += G * other.Mass * g * dy
+					}
+				}
+			}
+			e.Acceleration = NewVector2D(ax, ay)
+		}
+	}
+}
+
+// buildfmmtree returns every level of a uniform quadtree over the square bounding the entities, with the
+// entities sorted into the leaves of the deepest level
+func buildfmmtree(entities []*Entity, depth int, terms int) [][]*fmmbox {
+	minx, miny := math.Inf(1), math.Inf(1)
+	maxx, maxy := math.Inf(-1), math.Inf(-1)
+	for _, e := range entities {
+		minx, maxx = math.Min(minx, e.Position.X), math.Max(maxx, e.Position.X)
+		miny, maxy = math.Min(miny, e.Position.Y), math.Max(maxy, e.Position.Y)
+	}
+	size := math.Max(math.Max(maxx-minx, maxy-miny), 1e-9)
+
+	levels := make([][]*fmmbox, depth+1)
+	for level := range levels {
+		width := 1 << uint(level)
+		boxwidth := size / float64(width)
+		levels[level] = make([]*fmmbox, width*width)
+		for index := range levels[level] {
+			center := complex(minx+(float64(index%width)+0.5)*boxwidth, miny+(float64(index/width)+0.5)*boxwidth)
+			levels[level][index] = &fmmbox{center: center, multipole: make(expansion, terms*terms), local: make(expansion, terms*terms)}
+		}
+	}
+
+	side := 1 << uint(depth)
+	for _, e := range entities {
+		ix := clampindex(int((e.Position.X-minx)/size*float64(side)), side)
+		iy := clampindex(int((e.Position.Y-miny)/size*float64(side)), side)
+		leaf := levels[depth][iy*side+ix]
+		leaf.entities = append(leaf.entities, e)
+	}
+	return levels
+}
+
+// add adds the coefficients of another expansion of the same order
+func (c expansion) add(other expansion) {
+	for n := range c {
+		c[n] += other[n]
+	}
+}
+
+// shiftmultipole returns the multipole moments about a center offset from the original center by -shift, where
+// shift is the original center minus the new center
+func (c expansion) shiftmultipole(shift complex128, terms int) expansion {
+	// Moments of (d + shift)^k conj(d + shift)^l expand binomially in both factors, so with S[k][a] the binomial
+	// coefficient times shift^(k - a) the shifted moments are S M S^H
+	binomials := binomialpowers(shift, terms)
+	return sandwich(binomials, c, binomials, terms, complex(1, 0))
+}
+
+// shiftlocal returns the local expansion about a center offset from the original center by shift
+func (c expansion) shiftlocal(shift complex128, terms int) expansion {
+	// Expanding (shift + t)^a conj(shift + t)^b binomially collects the terms of t^a' conj(t)^b', so with
+	// S[a][a'] the binomial coefficient times shift^(a - a') the shifted coefficients are S^T L conj(S)
+	binomials := binomialpowers(shift, terms)
+	transposed := make([]complex128, len(binomials))
+	for a := 0; a < terms; a++ {
+		for a2 := 0; a2 < terms; a2++ {
+			transposed[a2*terms+a] = binomials[a*terms+a2]
+		}
+	}
+	return sandwich(transposed, c, transposed, terms, complex(1, 0))
+}
+
+// tolocal returns the local expansion about a center offset by separation from the center of these multipole moments
+func (c expansion) tolocal(separation complex128, terms int, coefficients [][]float64) expansion {
+	// With T[a][k] the coefficient times R^-(a+k) the local coefficients are |R|^-1 T M T^H
+	inverse := 1 / separation
+	powers := make([]complex128, 2*terms)
+	powers[0] = 1
+	for n := 1; n < len(powers); n++ {
+		powers[n] = powers[n-1] * inverse
+	}
+	translation := make([]complex128, terms*terms)
+	for a := 0; a < terms; a++ {
+		for k := 0; k < terms; k++ {
+			translation[a*terms+k] = complex(coefficients[a][k], 0) * powers[a+k]
+		}
+	}
+	return sandwich(translation, c, translation, terms, complex(1/cmplx.Abs(separation), 0))
+}
+
+// sandwich returns scale * A M B^H for square matrices flattened row by row. Every expansion of a real potential
+// is Hermitian, and A M A^H stays Hermitian, so only the upper triangle is computed when A and B are the same
+func sandwich(a []complex128, m expansion, b []complex128, terms int, scale complex128) expansion {
+	half := make([]complex128, terms*terms)
+	for i := 0; i < terms; i++ {
+		for l := 0; l < terms; l++ {
+			var sum complex128
+			for k := 0; k < terms; k++ {
+				sum += a[i*terms+k] * m[k*terms+l]
+			}
+			half[i*terms+l] = sum
+		}
+	}
+
+	result := make(expansion, terms*terms)
+	for i := 0; i < terms; i++ {
+		for j := i; j < terms; j++ {
+			var sum complex128
+			for l := 0; l < terms; l++ {
+				sum += half[i*terms+l] * cmplx.Conj(b[j*terms+l])
+			}
+			result[i*terms+j] = scale * sum
+			result[j*terms+i] = cmplx.Conj(result[i*terms+j])
+		}
+	}
+	return result
+}
+
+// gradient returns the gradient of the real potential described by the local expansion at offset t, as x + iy
+func (c expansion) gradient(t complex128, terms int) complex128 {
+	// For a real function f of z the gradient is 2 * conj(df/dz)
+	tbar := cmplx.Conj(t)
+	var derivative complex128
+	ta := complex(1, 0)
+	for a := 1; a < terms; a++ {
+		tab := ta
+		for b := 0; b < terms; b++ {
+			derivative += complex(float64(a), 0) * c[a*terms+b] * tab
+			tab *= tbar
+		}
+		ta *= t
+	}
+	return 2 * cmplx.Conj(derivative)
+}
+
+// binomialpowers returns the table binomial(k, a) * shift^(k - a) flattened to k * terms + a
+func binomialpowers(shift complex128, terms int) []complex128 {
+	powers := make([]complex128, terms)
+	powers[0] = 1
+	for n := 1; n < terms; n++ {
+		powers[n] = powers[n-1] * shift
+	}
+
+	table := make([]complex128, terms*terms)
+	for k := 0; k < terms; k++ {
+		binomial := 1.0
+		for a := k; a >= 0; a-- {
+			table[k*terms+a] = complex(binomial, 0) * powers[k-a]
+			binomial = binomial * float64(a) / float64(k-a+1)
+		}
+	}
+	return table
+}
+
+// fmmcoefficients returns the real coefficients (-1)^a (1/2)_(a+k) / (a! k!) of the multipole to local translation
+func fmmcoefficients(terms int) [][]float64 {
+	// Rising factorials (1/2)_n = (1/2)(3/2)...(n - 1/2) built up incrementally
+	rising := make([]float64, 2*terms)
+	rising[0] = 1
+	for n := 1; n < len(rising); n++ {
+		rising[n] = rising[n-1] * (float64(n) - 0.5)
+	}
+	factorial := make([]float64, terms)
+	factorial[0] = 1
+	for n := 1; n < terms; n++ {
+		factorial[n] = factorial[n-1] * float64(n)
+	}
+
+	coefficients := make([][]float64, terms)
+	for a := range coefficients {
+		coefficients[a] = make([]float64, terms)
+		sign := 1.0
+		if a%2 == 1 {
+			sign = -1
+		}
+		for k := range coefficients[a] {
+			coefficients[a][k] = sign * rising[a+k] / (factorial[a] * factorial[k])
+		}
+	}
+	return coefficients
+}
+
+// clampindex returns the index limited to a grid of the given side
+func clampindex(index int, side int) int {
+	if index < 0 {
+		return 0
+	}
+	if index >= side {
+		return side - 1
+	}
+	return index
+}
+
+// absint returns the absolute value of an int
+func absint(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
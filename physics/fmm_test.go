@@ -0,0 +1,63 @@
+package physics
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFMMSolverAccuracy(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		order     int
+		leafsize  int
+		count     int
+		tolerance float64
+	}{
+		{4, 8, 500, 1e-4},
+		{8, 8, 500, 1e-6},
+		{12, 8, 500, 1e-7},
+		{8, 16, 2000, 1e-5},
+		{0, 0, 300, 1e-4},
+		{8, 8, 3, 1e-3},
+	}
+
+	for _, c := range cases {
+		entities := randomentities(c.count, 300, 2)
+		worst := accelerationerror(entities, FMMSolver{Order: c.order, LeafSize: c.leafsize}, Softening{})
+		if worst > c.tolerance {
+			t.Errorf("Computing FMM accelerations of %v entities with order %v: error %v - expected at most %v", c.count, c.order, worst, c.tolerance)
+		}
+	}
+}
+
+// BenchmarkSolvers compares the solvers over growing entity counts to find where each overtakes direct summation
+func BenchmarkSolvers(b *testing.B) {
+	solvers := []struct {
+		name   string
+		solver Solver
+	}{
+		{"Direct", DirectSolver{}},
+		{"Pairwise", PairwiseSolver{}},
+		{"BarnesHut", BarnesHutSolver{Theta: 0.5}},
+		{"FMMOrder4", FMMSolver{Order: 4}},
+		{"FMMOrder8", FMMSolver{Order: 8}},
+	}
+
+	for _, count := range []int{30, 100, 300, 1000, 3000, 10000, 30000} {
+		entities := randomentities(count, 300, 3)
+		for _, s := range solvers {
+			// Direct summation is long past its crossover by here and would dominate the run
+			if _, pairwise := s.solver.(PairwiseSolver); pairwise && count > 3000 {
+				continue
+			}
+			if _, direct := s.solver.(DirectSolver); direct && count > 3000 {
+				continue
+			}
+			b.Run(fmt.Sprintf("%v/%v", s.name, count), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					s.solver.Accelerate(entities)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package physics
+
+import "math"
+
+// Hermite is the fourth order Hermite predictor-corrector integrator. Entities
+// share a single step size chosen by the Aarseth criterion from the
+// acceleration, jerk and their higher derivatives, scaled by Eta. Solvers that
+// are not JerkSolvers are bypassed in favour of direct summation with the same
+// softening, over nearest images for the periodic PMSolver
+type Hermite struct {
+	Eta float64
+
+	// Count of steps taken since creation
+	Steps int
+
+	// Step size suggested by the last step
+	step float64
+}
+
+// Accuracy parameter for the first step, where the higher derivatives needed by the Aarseth criterion are unknown
+const hermitestarteta float64 = 0.01
+
+// NewHermite returns a new Hermite integrator with the given accuracy parameter
+func NewHermite(eta float64) *Hermite {
+	return &Hermite{Eta: eta}
+}
+
+// Step advances the entities by dt, taking as many internal steps as the Aarseth criterion requires
+func (i *Hermite) Step(entities []*Entity, solver Solver, dt float64) {
+	i.Advance(entities, solver, dt)
+}
+
+// Advance advances the entities by duration, taking as many internal steps as the Aarseth criterion requires
+func (i *Hermite) Advance(entities []*Entity, solver Solver, duration float64) {
+	if duration <= 0 || len(entities) == 0 {
+		return
+	}
+
+	jerksolver := jerksolverof(solver)
+	jerksolver.AccelerateWithJerk(entities, entities)
+	if i.step <= 0 {
+		i.step = startingstep(entities)
+	}
+
+	positions := make([]Point, len(entities))
+	velocities := make([]Vector2D, len(entities))
+	accelerations := make([]Vector2D, len(entities))
+	jerks := make([]Vector2D, len(entities))
+
+	elapsed := 0.0
+	for elapsed < duration {
+		// Clip the step to land exactly on duration
+		dt := i.step
+		clipped := dt >= duration-elapsed
+		if clipped {
+			dt = duration - elapsed
+		}
+
+		// Predict positions and velocities from the Taylor series in acceleration and jerk
+		for n, e := range entities {
+			positions[n], velocities[n], accelerations[n], jerks[n] = e.Position, e.Velocity, e.Acceleration, e.Jerk
+			e.Position, e.Velocity = hermitepredict(positions[n], velocities[n], accelerations[n], jerks[n], dt)
+		}
+
+		// Correct with the acceleration and jerk evaluated at the predicted state
+		jerksolver.AccelerateWithJerk(entities, entities)
+		next := math.Inf(1)
+		for n, e := range entities {
+			var criterion float64
+			e.Position, e.Velocity, criterion = hermitecorrect(i.Eta, positions[n], velocities[n], accelerations[n], jerks[n], e.Acceleration, e.Jerk, dt)
+			next = math.Min(next, criterion)
+		}
+
+		i.Steps++
+		elapsed += dt
+		if clipped {
+			elapsed = duration
+		}
+
+		// A step clipped to land on duration is shorter than the criterion was tuned for, so it may only shrink the
+		// step or double it, and an undefined criterion keeps the old step
+		if math.IsInf(next, 1) || math.IsNaN(next) {
+			continue
+		}
+		if clipped {
+			next = math.Min(next, 2*i.step)
+		}
+		i.step = next
+	}
+
+	// Leave the accelerations and jerks consistent with the corrected state
+	jerksolver.AccelerateWithJerk(entities, entities)
+}
+
+// hermitepredict returns the position and velocity dt after the given state from the Taylor series in acceleration and jerk
+func hermitepredict(position Point, velocity Vector2D, acceleration Vector2D, jerk Vector2D, dt float64) (Point, Vector2D) {
+	predictedposition := position.Add(velocity.Scalarmul(dt)).Add(acceleration.Scalarmul(dt * dt / 2)).Add(jerk.Scalarmul(dt * dt * dt / 6))
+	predictedvelocity := velocity.Add(acceleration.Scalarmul(dt)).Add(jerk.Scalarmul(dt * dt / 2))
+	return predictedposition, predictedvelocity
+}
+
+// hermitecorrect returns the corrected position and velocity dt after the starting state given the acceleration
+// and jerk at the start and at the predicted end of the step, along with the Aarseth criterion for the next step
+func hermitecorrect(eta float64, position Point, velocity Vector2D, a0 Vector2D, j0 Vector2D, a1 Vector2D, j1 Vector2D, dt float64) (Point, Vector2D, float64) {
+	correctedvelocity := velocity.Add(a0.Add(a1).Scalarmul(dt / 2)).Add(j0.Subtract(j1).Scalarmul(dt * dt / 12))
+	correctedposition := position.Add(velocity.Add(correctedvelocity).Scalarmul(dt / 2)).Add(a0.Subtract(a1).Scalarmul(dt * dt / 12))
+
+	// Snap and crackle at the end of the step from the Hermite interpolant
+	crackle := a0.Subtract(a1).Scalarmul(12).Add(j0.Add(j1).Scalarmul(6 * dt)).Scalarmul(1 / (dt * dt * dt))
+	snap := a0.Subtract(a1).Scalarmul(-6).Subtract(j0.Scalarmul(4 * dt)).Subtract(j1.Scalarmul(2 * dt)).Scalarmul(1 / (dt * dt)).Add(crackle.Scalarmul(dt))
+
+	return correctedposition, correctedvelocity, aarsethstep(eta, a1, j1, snap, crackle)
+}
+
+// aarsethstep returns the Aarseth timestep criterion for a single entity
+func aarsethstep(eta float64, acceleration Vector2D, jerk Vector2D, snap Vector2D, crackle Vector2D) float64 {
+	a, j, s, c := acceleration.Length(), jerk.Length(), snap.Length(), crackle.Length()
+	denominator := j*c + s*s
+	if denominator == 0 {
+		return math.Inf(1)
+	}
+	return math.Sqrt(eta * (a*s + j*j) / denominator)
+}
+
+// startingstep returns a conservative first step for all entities from the ratio of acceleration to jerk
+func startingstep(entities []*Entity) float64 {
+	step := math.Inf(1)
+	for _, e := range entities {
+		step = math.Min(step, entitystartingstep(e))
+	}
+	return step
+}
+
+// entitystartingstep returns a conservative first step for a single entity from the ratio of acceleration to jerk
+func entitystartingstep(e *Entity) float64 {
+	j := e.Jerk.Length()
+	if j == 0 || e.Acceleration.Length() == 0 {
+		return defaultdt
+	}
+	return hermitestarteta * e.Acceleration.Length() / j
+}
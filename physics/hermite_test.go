@@ -0,0 +1,117 @@
+package physics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestHermiteCircularOrbit(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		eta       float64
+		tolerance float64
+	}{
+		{0.1, 1e-3},
+		{0.01, 1e-5},
+		{0.001, 1e-8},
+	}
+
+	for _, c := range cases {
+		radius := 100.0
+		entities, period := circularorbit(radius)
+		integrator := NewHermite(c.eta)
+		integrator.Advance(entities, DirectSolver{}, period)
+		if drift := math.Abs(entities[0].Distance(entities[1])-radius) / radius; drift > c.tolerance {
+			t.Errorf("Integrating a circular orbit with eta %v drifted by %v - expected at most %v", c.eta, drift, c.tolerance)
+		}
+	}
+}
+
+func TestHermiteAdvance(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entity   *Entity
+		duration float64
+		expected Point
+	}{
+		{NewEntity(1, 0, 0, 1, 2, 0, 0), 0.5, NewPoint(0.5, 1)},
+		{NewEntity(1, 1, 1, -2, 0, 0, 0), 10, NewPoint(-19, 1)},
+		{NewEntity(1, 3, 4, 5, 6, 0, 0), 0, NewPoint(3, 4)},
+	}
+
+	for _, c := range cases {
+		NewHermite(0.01).Advance([]*Entity{c.entity}, DirectSolver{}, c.duration)
+		if pointsinequal(c.entity.Position, c.expected, testprecision) {
+			t.Errorf("Advancing %v by %v got %v - expected %v", c.entity, c.duration, c.entity.Position, c.expected)
+		}
+	}
+}
+
+func TestHermiteStepSize(t *testing.T) {
+	t.Parallel()
+	entities, period := eccentricorbit(200, 0.3)
+	integrator := NewHermite(0.01)
+
+	// Starting at apocenter, half a period later the planet is at pericenter and a full period later back at apocenter
+	integrator.Advance(entities, DirectSolver{}, period/2)
+	pericenterstep := integrator.step
+	integrator.Advance(entities, DirectSolver{}, period/2)
+	apocenterstep := integrator.step
+
+	if pericenterstep*10 > apocenterstep {
+		t.Errorf("Step size at pericenter %v not much smaller than at apocenter %v", pericenterstep, apocenterstep)
+	}
+	if drift := math.Abs(entities[1].Position.X-200) / 200; drift > 1e-5 {
+		t.Errorf("Integrating an eccentric orbit drifted by %v - expected at most %v", drift, 1e-5)
+	}
+}
+
+func TestHermiteStepSizeInTicks(t *testing.T) {
+	t.Parallel()
+	entities, period := eccentricorbit(200, 0.3)
+	integrator := NewHermite(0.01)
+
+	// Ticks shorter than any step the orbit calls for still let the step follow the planet round it
+	ticks := 20000
+	var pericenterstep float64
+	for tick := 1; tick <= ticks; tick++ {
+		integrator.Advance(entities, DirectSolver{}, period/float64(ticks))
+		if tick == ticks/2 {
+			pericenterstep = integrator.step
+		}
+	}
+	apocenterstep := integrator.step
+
+	if pericenterstep*10 > apocenterstep {
+		t.Errorf("Step size at pericenter %v not much smaller than at apocenter %v advancing in ticks of %v", pericenterstep, apocenterstep, period/float64(ticks))
+	}
+}
+
+func TestHermiteSolverFallback(t *testing.T) {
+	t.Parallel()
+	softening := Softening{Plummer, 5}
+	cases := []struct {
+		solver   Solver
+		fallback JerkSolver
+	}{
+		// Solvers without jerks are replaced by direct summation keeping their softening
+		{BarnesHutSolver{Theta: 0.5, Softening: softening}, DirectSolver{Softening: softening}},
+		{PairwiseSolver{Softening: softening}, DirectSolver{Softening: softening}},
+		// And periodic ones by the nearest images in their domain
+		{PMSolver{Grid: 64, Domain: NewDomain(40, 40)}, MinimumImageSolver{Domain: NewDomain(40, 40)}},
+	}
+
+	for _, c := range cases {
+		entities := []*Entity{NewEntity(100, -18, 0, 0, 1, 0, 0), NewEntity(100, 18, 0, 0, -1, 0, 0)}
+		expected := []*Entity{NewEntity(100, -18, 0, 0, 1, 0, 0), NewEntity(100, 18, 0, 0, -1, 0, 0)}
+		NewHermite(0.01).Advance(entities, c.solver, 1)
+		NewHermite(0.01).Advance(expected, c.fallback, 1)
+		for n := range entities {
+			if !reflect.DeepEqual(entities[n].Position, expected[n].Position) {
+				t.Errorf("Advancing with %#v: got %v - expected %v as with %#v", c.solver, entities[n], expected[n], c.fallback)
+			}
+		}
+	}
+}
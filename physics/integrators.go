@@ -0,0 +1,130 @@
+package physics
+
+// Integrator advances a whole slice of entities by a single time step, using
+// the solver to evaluate gravitational accelerations at whatever intermediate
+// states the scheme requires
+type Integrator interface {
+	Step(entities []*Entity, solver Solver, dt float64)
+}
+
+// Euler is the first order explicit Euler scheme: position is advanced with
+// the old velocity and velocity with the old acceleration
+type Euler struct{}
+
+// Step advances the entities by dt with the explicit Euler scheme
+func (i Euler) Step(entities []*Entity, solver Solver, dt float64) {
+	solver.Accelerate(entities)
+	for _, e := range entities {
+		e.Update(dt)
+	}
+}
+
+// SemiImplicitEuler is the first order symplectic Euler scheme: velocity is
+// advanced first and the new velocity is used to advance position
+type SemiImplicitEuler struct{}
+
+// Step advances the entities by dt with the semi-implicit Euler scheme
+func (i SemiImplicitEuler) Step(entities []*Entity, solver Solver, dt float64) {
+	solver.Accelerate(entities)
+	for _, e := range entities {
+		e.Velocity = e.Velocity.Add(e.Acceleration.Scalarmul(dt))
+		e.Position = e.Position.Add(e.Velocity.Scalarmul(dt))
+	}
+}
+
+// VelocityVerlet is the second order velocity Verlet scheme
+type VelocityVerlet struct{}
+
+// Step advances the entities by dt with the velocity Verlet scheme
+func (i VelocityVerlet) Step(entities []*Entity, solver Solver, dt float64) {
+	solver.Accelerate(entities)
+
+	// Advance positions with the starting velocity and acceleration, holding on to the starting accelerations
+	old := make([]Vector2D, len(entities))
+	for n, e := range entities {
+		old[n] = e.Acceleration
+		e.Position = e.Position.Add(e.Velocity.Scalarmul(dt)).Add(e.Acceleration.Scalarmul(dt * dt / 2))
+	}
+
+	// Advance velocities with the average of the starting and ending accelerations
+	solver.Accelerate(entities)
+	for n, e := range entities {
+		e.Velocity = e.Velocity.Add(old[n].Add(e.Acceleration).Scalarmul(dt / 2))
+	}
+}
+
+// Leapfrog is the second order kick-drift-kick leapfrog scheme
+type Leapfrog struct{}
+
+// Step advances the entities by dt with a half kick, a full drift and a half kick
+func (i Leapfrog) Step(entities []*Entity, solver Solver, dt float64) {
+	solver.Accelerate(entities)
+	kick(entities, dt/2)
+	drift(entities, dt)
+	solver.Accelerate(entities)
+	kick(entities, dt/2)
+}
+
+// RK4 is the classic fourth order Runge-Kutta scheme
+type RK4 struct{}
+
+// Step advances the entities by dt with the classic Runge-Kutta scheme
+func (i RK4) Step(entities []*Entity, solver Solver, dt float64) {
+	n := len(entities)
+	positions := make([]Point, n)
+	velocities := make([]Vector2D, n)
+	for j, e := range entities {
+		positions[j] = e.Position
+		velocities[j] = e.Velocity
+	}
+
+	// Weighted sums of the position and velocity derivatives of every stage
+	dpos := make([]Vector2D, n)
+	dvel := make([]Vector2D, n)
+	for j := range entities {
+		dpos[j] = NewVector2D(0, 0)
+		dvel[j] = NewVector2D(0, 0)
+	}
+
+	// Each stage is evaluated at the starting state offset by the previous stage's derivatives
+	offsets := []float64{0, dt / 2, dt / 2, dt}
+	weights := []float64{1, 2, 2, 1}
+	for stage := range offsets {
+		solver.Accelerate(entities)
+		for j, e := range entities {
+			dpos[j] = dpos[j].Add(e.Velocity.Scalarmul(weights[stage]))
+			dvel[j] = dvel[j].Add(e.Acceleration.Scalarmul(weights[stage]))
+		}
+
+		if stage+1 == len(offsets) {
+			break
+		}
+		next := offsets[stage+1]
+		for j, e := range entities {
+			e.Position = positions[j].Add(e.Velocity.Scalarmul(next))
+			e.Velocity = velocities[j].Add(e.Acceleration.Scalarmul(next))
+		}
+	}
+
+	for j, e := range entities {
+		e.Position = positions[j].Add(dpos[j].Scalarmul(dt / 6))
+		e.Velocity = velocities[j].Add(dvel[j].Scalarmul(dt / 6))
+	}
+
+	// Leave the accelerations consistent with the final state
+	solver.Accelerate(entities)
+}
+
+// kick advances the velocity of every entity by its acceleration over dt
+func kick(entities []*Entity, dt float64) {
+	for _, e := range entities {
+		e.Velocity = e.Velocity.Add(e.Acceleration.Scalarmul(dt))
+	}
+}
+
+// drift advances the position of every entity by its velocity over dt
+func drift(entities []*Entity, dt float64) {
+	for _, e := range entities {
+		e.Position = e.Position.Add(e.Velocity.Scalarmul(dt))
+	}
+}
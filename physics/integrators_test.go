@@ -0,0 +1,82 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"math"
+	"testing"
+)
+
+// circularorbit returns a light planet in a circular orbit of the given radius around a heavy star,
+// along with the period of that orbit
+func circularorbit(radius float64) ([]*Entity, float64) {
+	starmass := 1000.0
+	speed := math.Sqrt(G * starmass / radius)
+	star := NewEntity(starmass, 0, 0, 0, 0, 0, 0)
+	planet := NewEntity(1e-6, radius, 0, 0, speed, 0, 0)
+	return []*Entity{star, planet}, 2 * math.Pi * radius / speed
+}
+
+func TestIntegratorFreeMotion(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		integrator Integrator
+		entity     *Entity
+		dt         float64
+		expected   Point
+	}{
+		{Euler{}, NewEntity(1, 0, 0, 1, 2, 0, 0), 0.5, NewPoint(0.5, 1)},
+		{SemiImplicitEuler{}, NewEntity(1, 1, 1, -2, 0, 0, 0), 0.25, NewPoint(0.5, 1)},
+		{VelocityVerlet{}, NewEntity(1, 0, 0, 3, 4, 5, 6), 1, NewPoint(3, 4)},
+		{Leapfrog{}, NewEntity(1, -1, -1, 0, 0, 0, 0), 1, NewPoint(-1, -1)},
+		{RK4{}, NewEntity(1, 2, 3, 1, 1, 0, 0), 0.1, NewPoint(2.1, 3.1)},
+		{ForestRuth{}, NewEntity(1, 0, 0, -1, 1, 0, 0), 2, NewPoint(-2, 2)},
+		{Yoshida4{}, NewEntity(1, 0, 0, 0.5, 0, 0, 0), 1, NewPoint(0.5, 0)},
+		{Yoshida6{}, NewEntity(1, 1, 0, 0, -1, 0, 0), 3, NewPoint(1, -3)},
+	}
+
+	for _, c := range cases {
+		c.integrator.Step([]*Entity{c.entity}, DirectSolver{}, c.dt)
+		if pointsinequal(c.entity.Position, c.expected, testprecision) {
+			t.Errorf("Stepping %T by %v got %v - expected %v", c.integrator, c.dt, c.entity.Position, c.expected)
+		}
+	}
+}
+
+func TestIntegratorCircularOrbit(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		integrator Integrator
+		tolerance  float64
+	}{
+		{Euler{}, 1e-1},
+		{SemiImplicitEuler{}, 1e-2},
+		{VelocityVerlet{}, 1e-4},
+		{Leapfrog{}, 1e-4},
+		{RK4{}, 1e-8},
+		{ForestRuth{}, 1e-8},
+		{Yoshida4{}, 1e-8},
+		{Yoshida6{}, 1e-8},
+	}
+
+	for _, c := range cases {
+		radius := 100.0
+		entities, period := circularorbit(radius)
+		steps := 1000
+		worst := 0.0
+		for i := 0; i < steps; i++ {
+			c.integrator.Step(entities, DirectSolver{}, period/float64(steps))
+			drift := math.Abs(entities[0].Distance(entities[1])-radius) / radius
+			worst = math.Max(worst, drift)
+		}
+		if worst > c.tolerance {
+			t.Errorf("Integrating a circular orbit with %T drifted by %v - expected at most %v", c.integrator, worst, c.tolerance)
+		}
+	}
+}
+
+func pointsinequal(p1 Point, p2 Point, testprecision int) bool {
+	xinequal := utils.RoundPrecision(p1.X, testprecision) != p2.X
+	yinequal := utils.RoundPrecision(p1.Y, testprecision) != p2.Y
+	return xinequal || yinequal
+}
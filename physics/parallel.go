@@ -0,0 +1,59 @@
+package physics
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelSolver sums the gravitational acceleration of every pair of entities like DirectSolver, softened by
+// Softening, but splits the entities across Workers goroutines. Zero Workers uses one per GOMAXPROCS. Each entity's
+// acceleration is still summed by a single goroutine in slice order, so results match DirectSolver bit for bit
+// whatever the number of workers. Compensated sums accelerations with compensated summation like DirectSolver
+type ParallelSolver struct {
+	Workers     int
+	Softening   Softening
+	Compensated bool
+}
+
+// Accelerate updates the acceleration of every entity from every other entity in the slice
+func (s ParallelSolver) Accelerate(entities []*Entity) {
+	s.partition(entities, func(e *Entity) {
+		if s.Compensated {
+			e.UpdateCompensatedGravitationalAcceleration(entities, s.Softening)
+		} else {
+			e.UpdateSoftenedGravitationalAcceleration(entities, s.Softening)
+		}
+	})
+}
+
+// AccelerateWithJerk updates the acceleration and jerk of every active entity from every other entity in the slice
+func (s ParallelSolver) AccelerateWithJerk(active []*Entity, entities []*Entity) {
+	s.partition(active, func(e *Entity) {
+		e.UpdateSoftenedGravitationalAccelerationAndJerk(entities, s.Softening)
+	})
+}
+
+// partition calls update on every entity, handing each worker a contiguous run of the slice, and waits for all of
+// them to finish. The updates only write to the entity they are given
+func (s ParallelSolver) partition(entities []*Entity, update func(e *Entity)) {
+	workers := s.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(entities) {
+		workers = len(entities)
+	}
+
+	var wait sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		start, end := w*len(entities)/workers, (w+1)*len(entities)/workers
+		wait.Add(1)
+		go func(chunk []*Entity) {
+			defer wait.Done()
+			for _, e := range chunk {
+				update(e)
+			}
+		}(entities[start:end])
+	}
+	wait.Wait()
+}
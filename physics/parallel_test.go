@@ -0,0 +1,75 @@
+package physics
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestParallelSolverDeterministic(t *testing.T) {
+	t.Parallel()
+	softening := Softening{Plummer, 2}
+	entities := randomentities(101, 300, 5)
+
+	DirectSolver{Softening: softening}.Accelerate(entities)
+	expected := make([]Vector2D, len(entities))
+	for i, e := range entities {
+		expected[i] = e.Acceleration
+	}
+
+	for _, workers := range []int{0, 1, 2, 3, 8, 200} {
+		ParallelSolver{Workers: workers, Softening: softening}.Accelerate(entities)
+		for i, e := range entities {
+			if e.Acceleration != expected[i] {
+				t.Errorf("Computing parallel accelerations with %v workers: entity %v got %v - expected %v", workers, i, e.Acceleration, expected[i])
+				break
+			}
+		}
+	}
+}
+
+func TestParallelSolverJerk(t *testing.T) {
+	t.Parallel()
+	entities := randomentities(40, 300, 6)
+	for i, e := range entities {
+		e.Velocity = NewVector2D(float64(i%7)-3, float64(i%5)-2)
+	}
+	active := entities[5:25]
+
+	DirectSolver{}.AccelerateWithJerk(active, entities)
+	expected := make([][2]Vector2D, len(active))
+	for i, e := range active {
+		expected[i] = [2]Vector2D{e.Acceleration, e.Jerk}
+	}
+
+	for _, workers := range []int{1, 4, 7} {
+		ParallelSolver{Workers: workers}.AccelerateWithJerk(active, entities)
+		for i, e := range active {
+			if e.Acceleration != expected[i][0] || e.Jerk != expected[i][1] {
+				t.Errorf("Computing parallel accelerations and jerks with %v workers: entity %v got %v and %v - expected %v and %v", workers, i, e.Acceleration, e.Jerk, expected[i][0], expected[i][1])
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkParallelSolver(b *testing.B) {
+	// Powers of two up to the number of cores, and all of them
+	workers := []int{}
+	for w := 1; w < runtime.NumCPU(); w *= 2 {
+		workers = append(workers, w)
+	}
+	workers = append(workers, runtime.NumCPU())
+
+	for _, count := range []int{300, 3000} {
+		entities := randomentities(count, 300, 3)
+		for _, w := range workers {
+			solver := ParallelSolver{Workers: w}
+			b.Run(fmt.Sprintf("%v/Workers%v", count, w), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					solver.Accelerate(entities)
+				}
+			})
+		}
+	}
+}
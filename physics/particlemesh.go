@@ -0,0 +1,111 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Default mesh size used when a PMSolver leaves Grid unset
+const defaultpmgrid int = 128
+
+// PMSolver computes the gravitational acceleration of every entity with the
+// particle-mesh method on a Grid by Grid mesh spanning Domain, which repeats
+// periodically across the plane. Mass is deposited onto the mesh cloud-in-cell,
+// the potential is found with fast Fourier transforms and the mesh forces are
+// interpolated back to the entities with the same cloud-in-cell weights.
+// Entities see each other as if the plane were the midplane of a thin three
+// dimensional sheet, matching the inverse square law of direct summation at
+// separations of several cells, while forces under a couple of cells are
+// smoothed away. Grid must be a power of two, and defaults to 128 when unset
+type PMSolver struct {
+	Grid   int
+	Domain Domain
+}
+
+// Accelerate updates the acceleration of every entity from the mesh potential of all entities in the slice
+func (s PMSolver) Accelerate(entities []*Entity) {
+	s.Grid = s.grid()
+	n := s.Grid
+	dx, dy := s.Domain.Width()/float64(n), s.Domain.Height()/float64(n)
+	smoothing := math.Max(dx, dy)
+
+	// Deposit surface density onto the mesh
+	density := make([]complex128, n*n)
+	for _, e := range entities {
+		s.cloudincell(e.Position, func(index int, weight float64) {
+			density[index] += complex(e.Mass*weight/(dx*dy), 0)
+		})
+	}
+	fft2(density, n, false)
+
+	// The potential of a thin sheet solves Poisson's equation with potential(k) = -2 pi G density(k) / |k|, and the
+	// acceleration is its negated gradient i k potential(k). A Gaussian cell-sized filter damps the short wavelengths
+	// the mesh cannot resolve, and the mean density and the Nyquist modes exert nothing
+	accelx := make([]complex128, n*n)
+	accely := make([]complex128, n*n)
+	for row := 0; row < n; row++ {
+		ky := wavenumber(row, n, s.Domain.Height())
+		for col := 0; col < n; col++ {
+			kx := wavenumber(col, n, s.Domain.Width())
+			if (row == 0 && col == 0) || row == n/2 || col == n/2 {
+				continue
+			}
+			k := math.Hypot(kx, ky)
+			potential := density[row*n+col] * complex(-2*math.Pi*G/k*math.Exp(-k*k*smoothing*smoothing), 0)
+			accelx[row*n+col] = complex(0, -kx) * potential
+			accely[row*n+col] = complex(0, -ky) * potential
+		}
+	}
+	fft2(accelx, n, true)
+	fft2(accely, n, true)
+
+	// Interpolate the mesh accelerations back with the deposit weights so no entity accelerates itself
+	for _, e := range entities {
+		ax, ay := 0.0, 0.0
+		s.cloudincell(e.Position, func(index int, weight float64) {
+			ax += weight * real(accelx[index])
+			ay += weight * real(accely[index])
+		})
+		e.Acceleration = NewVector2D(ax, ay)
+	}
+}
+
+// grid returns the size of the mesh, the default if Grid is unset, panicking if it is not a power of two as the
+// fast Fourier transforms would silently give wrong forces
+func (s PMSolver) grid() int {
+	if s.Grid <= 0 {
+		return defaultpmgrid
+	}
+	if s.Grid&(s.Grid-1) != 0 {
+		panic(fmt.Sprintf("physics: PMSolver Grid %v is not a power of two", s.Grid))
+	}
+	return s.Grid
+}
+
+// cloudincell calls visit with the index and weight of each of the four mesh cells sharing the point's mass,
+// wrapping around the periodic edges of the domain
+func (s PMSolver) cloudincell(p Point, visit func(index int, weight float64)) {
+	n := s.Grid
+	wrapped := s.Domain.Wrap(p)
+
+	// Cell centers sit half a cell in from the domain edges
+	u := (wrapped.X-s.Domain.MinX)/s.Domain.Width()*float64(n) - 0.5
+	v := (wrapped.Y-s.Domain.MinY)/s.Domain.Height()*float64(n) - 0.5
+	col, row := math.Floor(u), math.Floor(v)
+	fx, fy := u-col, v-row
+
+	col0, row0 := (int(col)+n)%n, (int(row)+n)%n
+	col1, row1 := (col0+1)%n, (row0+1)%n
+	visit(row0*n+col0, (1-fx)*(1-fy))
+	visit(row0*n+col1, fx*(1-fy))
+	visit(row1*n+col0, (1-fx)*fy)
+	visit(row1*n+col1, fx*fy)
+}
+
+// wavenumber returns the angular wavenumber of a transform index on a periodic mesh of n cells spanning length
+func wavenumber(index int, n int, length float64) float64 {
+	if index >= n/2 {
+		index -= n
+	}
+	return 2 * math.Pi * float64(index) / length
+}
@@ -0,0 +1,81 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPMSolverAccuracy(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		grid      int
+		size      float64
+		tolerance float64
+	}{
+		{128, 1000, 2e-1},
+		{256, 1000, 3e-2},
+		{512, 1000, 2e-2},
+		{512, 2000, 4e-2},
+	}
+
+	for _, c := range cases {
+		// A ring of entities many cells apart, which the mesh resolves
+		entities := make([]*Entity, 8)
+		for i := range entities {
+			angle := 2 * math.Pi * float64(i) / float64(len(entities))
+			entities[i] = NewEntity(10+10*float64(i), 100*math.Cos(angle), 100*math.Sin(angle)+3*float64(i), 0, 0, 0, 0)
+		}
+		worst := accelerationerror(entities, PMSolver{Grid: c.grid, Domain: NewDomain(c.size, c.size)}, Softening{})
+		if worst > c.tolerance {
+			t.Errorf("Computing PM accelerations on a %v mesh spanning %v: error %v - expected at most %v", c.grid, c.size, worst, c.tolerance)
+		}
+	}
+}
+
+func TestPMSolverPeriodic(t *testing.T) {
+	t.Parallel()
+
+	// Entities either side of the domain edge are closer across the edge than through the middle
+	entities := []*Entity{NewEntity(100, 90, 0, 0, 0, 0, 0), NewEntity(100, -90, 0, 0, 0, 0, 0)}
+	PMSolver{Grid: 64, Domain: NewDomain(200, 200)}.Accelerate(entities)
+	if entities[0].Acceleration.X <= 0 || entities[1].Acceleration.X >= 0 {
+		t.Errorf("Computing PM accelerations across the periodic edge: got %v and %v - expected pulls towards the edge", entities[0].Acceleration, entities[1].Acceleration)
+	}
+
+	// Equal and opposite forces conserve momentum
+	if total := entities[0].Acceleration.Add(entities[1].Acceleration).Length(); total > 1e-9*entities[0].Acceleration.Length() {
+		t.Errorf("Computing PM accelerations of equal masses: net acceleration %v - expected none", total)
+	}
+
+	// A lone entity exerts no force on itself
+	lone := []*Entity{NewEntity(100, 33.3, -12.1, 0, 0, 0, 0)}
+	PMSolver{Grid: 64, Domain: NewDomain(200, 200)}.Accelerate(lone)
+	if length := lone[0].Acceleration.Length(); length > 1e-9 || math.IsNaN(length) {
+		t.Errorf("Computing PM acceleration of a lone entity: got %v - expected none", lone[0].Acceleration)
+	}
+}
+
+func TestPMSolverGrid(t *testing.T) {
+	t.Parallel()
+
+	// An unset grid falls back to the default mesh
+	entities := []*Entity{NewEntity(100, 90, 0, 0, 0, 0, 0), NewEntity(100, -90, 0, 0, 0, 0, 0)}
+	PMSolver{Domain: NewDomain(200, 200)}.Accelerate(entities)
+	unset := entities[0].Acceleration
+	PMSolver{Grid: defaultpmgrid, Domain: NewDomain(200, 200)}.Accelerate(entities)
+	if unset != entities[0].Acceleration || unset.X <= 0 {
+		t.Errorf("Computing PM accelerations on an unset grid: got %v - expected %v from the default grid", unset, entities[0].Acceleration)
+	}
+
+	// A grid that is not a power of two is refused rather than transformed wrongly
+	for _, grid := range []int{3, 100, 129} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Computing PM accelerations on a %v mesh: got no panic - expected a power of two to be demanded", grid)
+				}
+			}()
+			PMSolver{Grid: grid, Domain: NewDomain(200, 200)}.Accelerate(entities)
+		}()
+	}
+}
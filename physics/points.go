@@ -5,37 +5,46 @@ import (
 	"math"
 )
 
-type Point struct {
-	X float64
-	Y float64
+// PointOf is a position in the plane with coordinates of any floating point type
+type PointOf[T Float] struct {
+	X T
+	Y T
 }
 
+// Point is the double precision position used throughout the simulation
+type Point = PointOf[float64]
+
 // NewPoint creates a new Point with supplied x and y position
-func NewPoint(x float64, y float64) *Point {
-	return &Point{X: x, Y: y}
+func NewPoint(x float64, y float64) Point {
+	return Point{X: x, Y: y}
+}
+
+// NewPointOf creates a new PointOf with supplied x and y position
+func NewPointOf[T Float](x T, y T) PointOf[T] {
+	return PointOf[T]{X: x, Y: y}
 }
 
 // Add returns a new point resulting from following the supplied vector
-func (p *Point) Add(v *Vector2D) *Point {
-	return NewPoint(p.X+v.X, p.Y+v.Y)
+func (p PointOf[T]) Add(v VectorOf[T]) PointOf[T] {
+	return NewPointOf(p.X+v.X, p.Y+v.Y)
 }
 
 // Subtract returns a new point resulting from negatively following the supplied vector
-func (p *Point) Subtract(v *Vector2D) *Point {
-	return NewPoint(p.X-v.X, p.Y-v.Y)
+func (p PointOf[T]) Subtract(v VectorOf[T]) PointOf[T] {
+	return NewPointOf(p.X-v.X, p.Y-v.Y)
 }
 
 // Distance returns the planar distance between two points
-func (p1 *Point) Distance(p2 *Point) float64 {
-	return math.Sqrt(math.Pow(p2.X-p1.X, 2) + math.Pow(p2.Y-p1.Y, 2))
+func (p1 PointOf[T]) Distance(p2 PointOf[T]) T {
+	return T(math.Sqrt(math.Pow(float64(p2.X-p1.X), 2) + math.Pow(float64(p2.Y-p1.Y), 2)))
 }
 
 // DisplacementVector returns the vector from the original point to the supplied point
-func (p1 *Point) DisplacementVector(p2 *Point) *Vector2D {
-	return NewVector2D(p2.X-p1.X, p2.Y-p1.Y)
+func (p1 PointOf[T]) DisplacementVector(p2 PointOf[T]) VectorOf[T] {
+	return NewVectorOf(p2.X-p1.X, p2.Y-p1.Y)
 }
 
 // String returns the formatted string "Point{X: ..., Y: ...)"
-func (p *Point) String() string {
+func (p PointOf[T]) String() string {
 	return fmt.Sprintf("Point{X: %v, Y: %v)", p.X, p.Y)
 }
@@ -0,0 +1,39 @@
+package physics
+
+import "fmt"
+
+type Point3D struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// NewPoint3D creates a new Point3D with supplied x, y and z position
+func NewPoint3D(x float64, y float64, z float64) Point3D {
+	return Point3D{X: x, Y: y, Z: z}
+}
+
+// Add returns a new point resulting from following the supplied vector
+func (p Point3D) Add(v Vector3D) Point3D {
+	return NewPoint3D(p.X+v.X, p.Y+v.Y, p.Z+v.Z)
+}
+
+// Subtract returns a new point resulting from negatively following the supplied vector
+func (p Point3D) Subtract(v Vector3D) Point3D {
+	return NewPoint3D(p.X-v.X, p.Y-v.Y, p.Z-v.Z)
+}
+
+// Distance returns the distance between two points
+func (p1 Point3D) Distance(p2 Point3D) float64 {
+	return p1.DisplacementVector(p2).Length()
+}
+
+// DisplacementVector returns the vector from the original point to the supplied point
+func (p1 Point3D) DisplacementVector(p2 Point3D) Vector3D {
+	return NewVector3D(p2.X-p1.X, p2.Y-p1.Y, p2.Z-p1.Z)
+}
+
+// String returns the formatted string "Point3D{X: ..., Y: ..., Z: ...)"
+func (p Point3D) String() string {
+	return fmt.Sprintf("Point3D{X: %v, Y: %v, Z: %v)", p.X, p.Y, p.Z)
+}
@@ -0,0 +1,52 @@
+package physics
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPoint3DAddSubtract(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		point      Point3D
+		vector     Vector3D
+		sum        Point3D
+		difference Point3D
+	}{
+		{NewPoint3D(0, 0, 0), NewVector3D(1, 1, 1), NewPoint3D(1, 1, 1), NewPoint3D(-1, -1, -1)},
+		{NewPoint3D(8, 10, -2), NewVector3D(3, 8, 4), NewPoint3D(11, 18, 2), NewPoint3D(5, 2, -6)},
+		{NewPoint3D(1.25, 2.5, 0.5), NewVector3D(-0.75, 3.5, 0.25), NewPoint3D(0.5, 6, 0.75), NewPoint3D(2, -1, 0.25)},
+	}
+
+	for _, c := range cases {
+		if p := c.point.Add(c.vector); !reflect.DeepEqual(p, c.sum) {
+			t.Errorf("Computing %v + %v = %v - expected %v", c.point, c.vector, p, c.sum)
+		}
+		if p := c.point.Subtract(c.vector); !reflect.DeepEqual(p, c.difference) {
+			t.Errorf("Computing %v - %v = %v - expected %v", c.point, c.vector, p, c.difference)
+		}
+	}
+}
+
+func TestPoint3DDistance(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		p1           Point3D
+		p2           Point3D
+		displacement Vector3D
+		distance     float64
+	}{
+		{NewPoint3D(0, 0, 0), NewPoint3D(0, 0, 0), NewVector3D(0, 0, 0), 0},
+		{NewPoint3D(1, 1, 1), NewPoint3D(3, 4, 7), NewVector3D(2, 3, 6), 7},
+		{NewPoint3D(1, -2, 3), NewPoint3D(0, 2, -5), NewVector3D(-1, 4, -8), 9},
+	}
+
+	for _, c := range cases {
+		if displacement := c.p1.DisplacementVector(c.p2); !reflect.DeepEqual(displacement, c.displacement) {
+			t.Errorf("Computing displacement(%v, %v) = %v - expected %v", c.p1, c.p2, displacement, c.displacement)
+		}
+		if distance := c.p1.Distance(c.p2); distance != c.distance {
+			t.Errorf("Computing distance(%v, %v) = %v - expected %v", c.p1, c.p2, distance, c.distance)
+		}
+	}
+}
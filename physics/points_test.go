@@ -5,12 +5,22 @@ import (
 	"testing"
 )
 
+// pointof returns the point in the precision of T
+func pointof[T Float](p Point) PointOf[T] {
+	return NewPointOf(T(p.X), T(p.Y))
+}
+
+// pointsclose returns whether a point is within a few units of its precision of the expected point
+func pointsclose[T Float](p PointOf[T], expected Point) bool {
+	return closeto(p.X, expected.X) && closeto(p.Y, expected.Y)
+}
+
 func TestPointAdd(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		point    *Point
-		vector   *Vector2D
-		expected *Point
+		point    Point
+		vector   Vector2D
+		expected Point
 	}{
 		{NewPoint(0, 0), NewVector2D(1, 1), NewPoint(1, 1)},
 		{NewPoint(1, 1), NewVector2D(2, 3), NewPoint(3, 4)},
@@ -23,15 +33,18 @@ func TestPointAdd(t *testing.T) {
 		if !reflect.DeepEqual(p, c.expected) {
 			t.Errorf("Computing %v + %v = %v - expected %v", c.point, c.vector, p, c.expected)
 		}
+		if p := pointof[float32](c.point).Add(of[float32](c.vector)); !pointsclose(p, c.expected) {
+			t.Errorf("Computing float32 %v + %v = %v - expected %v", c.point, c.vector, p, c.expected)
+		}
 	}
 }
 
 func TestPointSubtract(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		point    *Point
-		vector   *Vector2D
-		expected *Point
+		point    Point
+		vector   Vector2D
+		expected Point
 	}{
 		{NewPoint(0, 0), NewVector2D(1, 1), NewPoint(-1, -1)},
 		{NewPoint(8, 10), NewVector2D(3, 8), NewPoint(5, 2)},
@@ -44,14 +57,17 @@ func TestPointSubtract(t *testing.T) {
 		if !reflect.DeepEqual(p, c.expected) {
 			t.Errorf("Computing %v - %v = %v - expected %v", c.point, c.vector, p, c.expected)
 		}
+		if p := pointof[float32](c.point).Subtract(of[float32](c.vector)); !pointsclose(p, c.expected) {
+			t.Errorf("Computing float32 %v - %v = %v - expected %v", c.point, c.vector, p, c.expected)
+		}
 	}
 }
 
 func TestPointDistance(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		p1       *Point
-		p2       *Point
+		p1       Point
+		p2       Point
 		expected float64
 	}{
 		{NewPoint(0, 0), NewPoint(0, 0), 0},
@@ -66,15 +82,18 @@ func TestPointDistance(t *testing.T) {
 		if distance != c.expected {
 			t.Errorf("Computing distance between %v and %v = %v - expected %v", c.p1, c.p2, distance, c.expected)
 		}
+		if distance := pointof[float32](c.p1).Distance(pointof[float32](c.p2)); !closeto(distance, c.expected) {
+			t.Errorf("Computing float32 distance between %v and %v = %v - expected %v", c.p1, c.p2, distance, c.expected)
+		}
 	}
 }
 
 func TestDisplacementVector(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		p1       *Point
-		p2       *Point
-		expected *Vector2D
+		p1       Point
+		p2       Point
+		expected Vector2D
 	}{
 		{NewPoint(0, 0), NewPoint(0, 0), NewVector2D(0, 0)},
 		{NewPoint(0, 0), NewPoint(0, 1), NewVector2D(0, 1)},
@@ -88,5 +107,8 @@ func TestDisplacementVector(t *testing.T) {
 		if !reflect.DeepEqual(dv, c.expected) {
 			t.Errorf("Computing displacement vector between %v and %v = %v - expected %v", c.p1, c.p2, dv, c.expected)
 		}
+		if dv := pointof[float32](c.p1).DisplacementVector(pointof[float32](c.p2)); !vectorsclose(dv, c.expected) {
+			t.Errorf("Computing float32 displacement vector between %v and %v = %v - expected %v", c.p1, c.p2, dv, c.expected)
+		}
 	}
 }
@@ -0,0 +1,172 @@
+package physics
+
+// Default step size of a new Simulation
+const defaultdt float64 = 0.01
+
+// Simulation holds a slice of entities along with the integrator and solver
+// used to advance them and the simulated time they have been advanced to.
+// An optional boundary and collider handle entities reaching the edges of the
+// simulated region and each other after every step, and an optional watchdog
+// then checks that the step did not blow up. Units sets the gravitational
+// constant the entities attract each other with, as measured in them
+type Simulation struct {
+	Entities   []*Entity
+	Integrator Integrator
+	Solver     Solver
+	Boundary   Boundary
+	Collider   Collider
+	Watchdog   *Watchdog
+	Units      Units
+
+	// Step size used by integrators that do not choose their own, the default step size when not positive
+	Dt float64
+
+	// Simulated seconds elapsed
+	Time float64
+}
+
+// NewSimulation returns a new Simulation of the given entities at time 0 using
+// velocity Verlet with direct summation in playground units
+func NewSimulation(entities []*Entity) *Simulation {
+	return &Simulation{Entities: entities, Integrator: VelocityVerlet{}, Solver: DirectSolver{}, Units: Playground, Dt: defaultdt}
+}
+
+// Tick advances the simulation by a single step of Dt, returning the
+// watchdog's alarm if it stopped the simulation
+func (s *Simulation) Tick() error {
+	if err := s.halted(); err != nil {
+		return err
+	}
+	solver := solverin(s.Solver, s.Units)
+	dt := s.dt()
+	s.Integrator.Step(s.Entities, solver, dt)
+	s.Time += dt
+	return s.resolve(solver, dt)
+}
+
+// AdvanceTo advances the simulation to the target time. Integrators that
+// choose their own step sizes cover the span in one call, with the boundary,
+// collider and watchdog acting once at the target, all others take steps of
+// Dt with the final step shortened to land on the target and those acting
+// after every step. If the watchdog stops the simulation it is left at the
+// bad step and the alarm is returned
+func (s *Simulation) AdvanceTo(target float64) error {
+	if err := s.halted(); err != nil || target <= s.Time {
+		return err
+	}
+
+	solver := solverin(s.Solver, s.Units)
+	if advancer, ok := s.Integrator.(Advancer); ok {
+		duration := target - s.Time
+		advancer.Advance(s.Entities, solver, duration)
+		s.Time = target
+		return s.resolve(solver, duration)
+	}
+
+	for dt := s.dt(); s.Time+dt < target; {
+		s.Integrator.Step(s.Entities, solver, dt)
+		s.Time += dt
+		if err := s.resolve(solver, dt); err != nil {
+			return err
+		}
+	}
+	dt := target - s.Time
+	s.Integrator.Step(s.Entities, solver, dt)
+	s.Time = target
+	return s.resolve(solver, dt)
+}
+
+// dt returns the step size of the simulation, falling back to the default when Dt is not positive
+func (s *Simulation) dt() float64 {
+	if s.Dt > 0 {
+		return s.Dt
+	}
+	return defaultdt
+}
+
+// resolve applies the boundary to the step of dt just taken, then lets the collider resolve contacts between the
+// entities at the current time, then lets the watchdog check them, skipping any there is none of. The watchdog is
+// told what the boundary and collider changed so it does not count that as drift, and measures energy in the units
+// of the simulation
+func (s *Simulation) resolve(solver Solver, dt float64) error {
+	var before []*Entity
+	if s.Watchdog != nil {
+		s.Watchdog.Units = s.Units
+		if s.Watchdog.MaxDrift > 0 {
+			before = snapshot(s.Entities)
+		}
+	}
+
+	if rebounder, ok := s.Boundary.(Rebounder); ok {
+		s.rebound(rebounder, solver, dt)
+	} else if s.Boundary != nil {
+		s.Entities = s.Boundary.Apply(s.Entities, dt)
+	}
+	if s.Collider != nil {
+		s.Entities = s.Collider.Collide(s.Entities, s.Time)
+	}
+	if s.Watchdog != nil {
+		s.Watchdog.Intervened(before, s.Entities)
+		return s.Watchdog.Check(s.Entities, s.Time)
+	}
+	return nil
+}
+
+// rebound stops entities at the walls of the boundary where they reached them during the step of dt, and integrates
+// each again over what was left of the step, still pulled by all the others. Integrators that choose their own
+// steps keep state for the whole system, so entities are stepped on with velocity Verlet instead. Entities still
+// outside after the most bounces are put on the wall
+func (s *Simulation) rebound(boundary Rebounder, solver Solver, dt float64) {
+	integrator := s.Integrator
+	if _, ok := integrator.(Advancer); ok {
+		integrator = VelocityVerlet{}
+	}
+
+	bounces := boundary.Rebound(s.Entities, dt)
+	for bounce := 0; bounce < maxwallbounces && len(bounces) > 0; bounce++ {
+		next := []Bounce{}
+		for _, b := range bounces {
+			rebounding := []*Entity{b.Entity}
+			integrator.Step(rebounding, partialsolver{solver, s.Entities}, b.Remainder)
+			next = append(next, boundary.Rebound(rebounding, b.Remainder)...)
+		}
+		bounces = next
+	}
+	for _, b := range bounces {
+		boundary.Apply([]*Entity{b.Entity}, 0)
+	}
+}
+
+// partialsolver accelerates some of the entities of a larger slice by all of them, leaving the accelerations of the
+// rest alone
+type partialsolver struct {
+	solver   Solver
+	entities []*Entity
+}
+
+// Accelerate updates the acceleration of the given entities from every entity of the full slice
+func (s partialsolver) Accelerate(entities []*Entity) {
+	accelerated := make(map[*Entity]bool, len(entities))
+	for _, e := range entities {
+		accelerated[e] = true
+	}
+	previous := make([]Vector2D, len(s.entities))
+	for n, e := range s.entities {
+		previous[n] = e.Acceleration
+	}
+
+	s.solver.Accelerate(s.entities)
+	for n, e := range s.entities {
+		if !accelerated[e] {
+			e.Acceleration = previous[n]
+		}
+	}
+}
+
+// halted returns the alarm of a watchdog refusing to let the simulation advance, if there is one
+func (s *Simulation) halted() error {
+	if s.Watchdog != nil {
+		return s.Watchdog.Halted()
+	}
+	return nil
+}
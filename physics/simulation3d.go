@@ -0,0 +1,144 @@
+package physics
+
+import "math"
+
+// Simulation3D holds a slice of entities free to move in three dimensions,
+// advanced with velocity Verlet and direct summation softened by Softening
+// in open space, and the simulated time they have been advanced to. Units
+// sets the gravitational constant like it does for Simulation
+type Simulation3D struct {
+	Entities  []*Entity3D
+	Softening Softening
+	Units     Units
+
+	// Step size of every step
+	Dt float64
+
+	// Simulated seconds elapsed
+	Time float64
+}
+
+// NewSimulation3D returns a new Simulation3D of the given entities at time 0
+// in playground units
+func NewSimulation3D(entities []*Entity3D) *Simulation3D {
+	return &Simulation3D{Entities: entities, Units: Playground, Dt: defaultdt}
+}
+
+// Tick advances the simulation by a single step of Dt
+func (s *Simulation3D) Tick() {
+	s.step(s.Dt)
+	s.Time += s.Dt
+}
+
+// AdvanceTo advances the simulation to the target time with steps of Dt,
+// the final step shortened to land on the target
+func (s *Simulation3D) AdvanceTo(target float64) {
+	if target <= s.Time {
+		return
+	}
+	for s.Time+s.Dt < target {
+		s.Tick()
+	}
+	s.step(target - s.Time)
+	s.Time = target
+}
+
+// Energy returns the total kinetic and potential energy of the entities,
+// with the potential softened like the forces
+func (s *Simulation3D) Energy() float64 {
+	kinetic, potential := s.energies()
+	return kinetic + potential
+}
+
+// EnergyDrift returns how far the energy has moved from a reference energy,
+// relative to the reference like Diagnostics.Drift, or to the sizes of the
+// kinetic and potential energies when the reference is zero
+func (s *Simulation3D) EnergyDrift(reference float64) float64 {
+	kinetic, potential := s.energies()
+	return relativechange(kinetic+potential, reference, kinetic+math.Abs(potential))
+}
+
+// energies returns the total kinetic and potential energy of the entities
+func (s *Simulation3D) energies() (float64, float64) {
+	kinetic, potential := 0.0, 0.0
+	for i, e1 := range s.Entities {
+		kinetic += e1.Mass * e1.Velocity.Dotproduct(e1.Velocity) / 2
+		for _, e2 := range s.Entities[i+1:] {
+			potential += s.Units.gravity() * e1.Mass * e2.Mass * s.Softening.potential(e1.Distance(e2))
+		}
+	}
+	return kinetic, potential
+}
+
+// Momentum returns the total linear momentum of the entities
+func (s *Simulation3D) Momentum() Vector3D {
+	momentum := NewVector3D(0, 0, 0)
+	for _, e := range s.Entities {
+		momentum = momentum.Add(e.Velocity.Scalarmul(e.Mass))
+	}
+	return momentum
+}
+
+// AngularMomentum returns the total angular momentum of the entities about
+// the origin
+func (s *Simulation3D) AngularMomentum() Vector3D {
+	angular := NewVector3D(0, 0, 0)
+	for _, e := range s.Entities {
+		r := NewPoint3D(0, 0, 0).DisplacementVector(e.Position)
+		angular = angular.Add(r.Crossproduct(e.Velocity.Scalarmul(e.Mass)))
+	}
+	return angular
+}
+
+// CenterOfMassFrame3D moves the entities into the frame of their barycenter like CenterOfMassFrame does in the
+// plane, returning the barycenter and its velocity, which were subtracted from every position and velocity
+func CenterOfMassFrame3D(entities []*Entity3D) (Point3D, Vector3D) {
+	origin := NewPoint3D(0, 0, 0)
+	mass, moment, momentum := 0.0, NewVector3D(0, 0, 0), NewVector3D(0, 0, 0)
+	for _, e := range entities {
+		mass += e.Mass
+		moment = moment.Add(origin.DisplacementVector(e.Position).Scalarmul(e.Mass))
+		momentum = momentum.Add(e.Velocity.Scalarmul(e.Mass))
+	}
+	if mass == 0 {
+		return origin, NewVector3D(0, 0, 0)
+	}
+
+	offset, velocity := moment.Scalarmul(1/mass), momentum.Scalarmul(1/mass)
+	for _, e := range entities {
+		e.Position = e.Position.Subtract(offset)
+		e.Velocity = e.Velocity.Subtract(velocity)
+	}
+	return origin.Add(offset), velocity
+}
+
+// step advances the entities by dt with the velocity Verlet scheme, taking
+// the same steps as VelocityVerlet with DirectSolver does in the plane
+func (s *Simulation3D) step(dt float64) {
+	s.accelerate()
+
+	// Advance positions with the starting velocity and acceleration, holding on to the starting accelerations
+	old := make([]Vector3D, len(s.Entities))
+	for n, e := range s.Entities {
+		old[n] = e.Acceleration
+		e.Position = e.Position.Add(e.Velocity.Scalarmul(dt)).Add(e.Acceleration.Scalarmul(dt * dt / 2))
+	}
+
+	// Advance velocities with the average of the starting and ending accelerations
+	s.accelerate()
+	for n, e := range s.Entities {
+		e.Velocity = e.Velocity.Add(old[n].Add(e.Acceleration).Scalarmul(dt / 2))
+	}
+}
+
+// accelerate updates the acceleration of every entity from every other entity,
+// scaled from G to the gravitational constant of the units
+func (s *Simulation3D) accelerate() {
+	scale := s.Units.gravity() / G
+	for _, e := range s.Entities {
+		e.UpdateSoftenedGravitationalAcceleration(s.Entities, s.Softening)
+		if scale != 1 {
+			e.Acceleration = e.Acceleration.Scalarmul(scale)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimulation3DMatchesPlanarSimulation(t *testing.T) {
+	t.Parallel()
+	entities := randomentities(16, 100, 3)
+	for _, e := range entities {
+		e.Velocity = NewVector2D(e.Position.Y/10, -e.Position.X/10)
+	}
+	s3d := NewSimulation3D(planar(entities))
+	s3d.Softening = Softening{Kernel: Plummer, Length: 5}
+	s := NewSimulation(entities)
+	s.Solver = DirectSolver{Softening: s3d.Softening}
+
+	s.AdvanceTo(1.005)
+	s3d.AdvanceTo(1.005)
+	if s3d.Time != s.Time {
+		t.Errorf("Advancing to 1.005 reached time %v - expected %v", s3d.Time, s.Time)
+	}
+	for n, e := range s.Entities {
+		e3d := s3d.Entities[n]
+		if e3d.Position != NewPoint3D(e.Position.X, e.Position.Y, 0) || e3d.Velocity != NewVector3D(e.Velocity.X, e.Velocity.Y, 0) {
+			t.Errorf("Advancing entity %v in 3D got %v - expected %v", n, e3d, e)
+		}
+	}
+}
+
+func TestSimulation3DInclinedOrbit(t *testing.T) {
+	t.Parallel()
+	entities, period := circularorbit(100)
+	s := NewSimulation3D(planar(entities))
+	s.Dt = period / 1000
+
+	// Tilt the orbit out of the plane about an axis that is neither in it nor normal to it
+	axis := NewVector3D(1, 2, 0.5)
+	for _, e := range s.Entities {
+		e.Position = NewPoint3D(0, 0, 0).Add(NewPoint3D(0, 0, 0).DisplacementVector(e.Position).Rotate(axis, 0.7))
+		e.Velocity = e.Velocity.Rotate(axis, 0.7)
+	}
+	energy := s.Energy()
+	angular := s.AngularMomentum()
+	planet := s.Entities[1]
+	start := planet.Position
+
+	s.AdvanceTo(period)
+	if drift := math.Abs(s.Energy()-energy) / math.Abs(energy); drift > 1e-6 {
+		t.Errorf("Inclined orbit energy drifted by %v", drift)
+	}
+	if drift := s.AngularMomentum().Subtract(angular).Length() / angular.Length(); drift > 1e-12 {
+		t.Errorf("Inclined orbit angular momentum drifted by %v", drift)
+	}
+	if miss := start.Distance(planet.Position) / 100; miss > 1e-3 {
+		t.Errorf("Inclined orbit returned to %v after a period - expected %v", planet.Position, start)
+	}
+
+	// The orbit stays in its tilted plane
+	normal := angular.Normalize()
+	if height := NewPoint3D(0, 0, 0).DisplacementVector(planet.Position).Dotproduct(normal); math.Abs(height) > 1e-9 {
+		t.Errorf("Inclined orbit left its plane by %v", height)
+	}
+}
+
+func TestSimulation3DTick(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	e := NewEntity3D(1, 5, 5, 5, -10, 100, 20, 0, 0, 0)
+	s := NewSimulation3D([]*Entity3D{e})
+	for i := 0; i < 10; i++ {
+		s.Tick()
+	}
+	position := NewPoint3D(0, 0, 0).DisplacementVector(e.Position)
+	if vectors3dinequal(position, NewVector3D(4, 15, 7), testprecision) {
+		t.Errorf("Ticking 10 times got %v - expected %v", e.Position, NewPoint3D(4, 15, 7))
+	}
+	if s.Momentum() != NewVector3D(-10, 100, 20) {
+		t.Errorf("Free entity momentum %v - expected %v", s.Momentum(), NewVector3D(-10, 100, 20))
+	}
+}
+
+func TestSimulation3DEnergyDrift(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		entities  []*Entity3D
+		reference float64
+		expected  float64
+	}{
+		// Relative to the reference energy
+		{[]*Entity3D{NewEntity3D(2, 0, 0, 0, 3, 0, 4, 0, 0, 0)}, 20, 0.25},
+		// Or to the sizes of the energies when the reference is zero
+		{[]*Entity3D{NewEntity3D(2, 0, 0, 0, 3, 0, 4, 0, 0, 0)}, 0, 1},
+		// And zero for nothing at all
+		{[]*Entity3D{}, 0, 0},
+	}
+
+	for _, c := range cases {
+		if drift := NewSimulation3D(c.entities).EnergyDrift(c.reference); drift != c.expected {
+			t.Errorf("Energy drift of %v from %v = %v - expected %v", c.entities, c.reference, drift, c.expected)
+		}
+	}
+}
+
+func TestCenterOfMassFrame3D(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		entities []*Entity3D
+		center   Point3D
+		velocity Vector3D
+	}{
+		{[]*Entity3D{}, NewPoint3D(0, 0, 0), NewVector3D(0, 0, 0)},
+		{[]*Entity3D{NewEntity3D(3, 1, 2, 3, 4, 0, -4, 0, 0, 0), NewEntity3D(1, 5, -2, -1, 0, 8, 4, 0, 0, 0)}, NewPoint3D(2, 1, 2), NewVector3D(3, 2, -2)},
+		// Massless entities are carried along without moving the barycenter
+		{[]*Entity3D{NewEntity3D(2, 10, 10, 10, 1, 1, 1, 0, 0, 0), NewEntity3D(0, 0, 0, 0, 0, 0, 0, 0, 0, 0)}, NewPoint3D(10, 10, 10), NewVector3D(1, 1, 1)},
+	}
+
+	for _, c := range cases {
+		center, velocity := CenterOfMassFrame3D(c.entities)
+		if center != c.center || velocity != c.velocity {
+			t.Errorf("Moving %v to its center of mass frame: subtracted %v and %v - expected %v and %v", c.entities, center, velocity, c.center, c.velocity)
+		}
+		simulation := NewSimulation3D(c.entities)
+		if momentum := simulation.Momentum().Length(); momentum > 1e-12 {
+			t.Errorf("Moving %v to its center of mass frame: got momentum %v - expected none", c.entities, momentum)
+		}
+		if center, _ := CenterOfMassFrame3D(c.entities); center.Distance(NewPoint3D(0, 0, 0)) > 1e-12 {
+			t.Errorf("Moving %v to its center of mass frame: got barycenter %v - expected the origin", c.entities, center)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"testing"
+	"time"
+)
+
+func TestSimulationTick(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entity   *Entity
+		ticks    int
+		expected Point
+	}{
+		{NewEntity(1, 0, 0, 1, 2, 0, 0), 0, NewPoint(0, 0)},
+		{NewEntity(1, 0, 0, 1, 2, 0, 0), 1, NewPoint(0.01, 0.02)},
+		{NewEntity(1, 5, 5, -10, 100, 0, 0), 10, NewPoint(4, 15)},
+	}
+
+	for _, c := range cases {
+		s := NewSimulation([]*Entity{c.entity})
+		for i := 0; i < c.ticks; i++ {
+			s.Tick()
+		}
+		if pointsinequal(c.entity.Position, c.expected, testprecision) {
+			t.Errorf("Ticking %v times got %v - expected %v", c.ticks, c.entity.Position, c.expected)
+		}
+		if time := float64(c.ticks) * s.Dt; utils.RoundPrecision(s.Time, testprecision) != time {
+			t.Errorf("Ticking %v times reached time %v - expected %v", c.ticks, s.Time, time)
+		}
+	}
+}
+
+func TestSimulationAdvanceTo(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		integrator Integrator
+		entity     *Entity
+		target     float64
+		expected   Point
+	}{
+		{VelocityVerlet{}, NewEntity(1, 0, 0, 1, 2, 0, 0), 0.125, NewPoint(0.125, 0.25)},
+		{RK4{}, NewEntity(1, 0, 0, 1, 2, 0, 0), 3, NewPoint(3, 6)},
+		{Euler{}, NewEntity(1, 1, 1, 1, 1, 0, 0), -1, NewPoint(1, 1)},
+		{NewDormandPrince(1e-6, 1e-6), NewEntity(1, 0, 0, -4, 4, 0, 0), 2.5, NewPoint(-10, 10)},
+	}
+
+	for _, c := range cases {
+		s := NewSimulation([]*Entity{c.entity})
+		s.Integrator = c.integrator
+		s.AdvanceTo(c.target)
+		if pointsinequal(c.entity.Position, c.expected, testprecision) {
+			t.Errorf("Advancing with %T to %v got %v - expected %v", c.integrator, c.target, c.entity.Position, c.expected)
+		}
+		if c.target > 0 && s.Time != c.target {
+			t.Errorf("Advancing with %T to %v reached time %v", c.integrator, c.target, s.Time)
+		}
+	}
+}
+
+func TestSimulationDefaultDt(t *testing.T) {
+	t.Parallel()
+	cases := []float64{0, -0.01}
+
+	// Simulations built without NewSimulation, or given a step that goes nowhere, step by the default instead
+	for _, dt := range cases {
+		s := &Simulation{Entities: []*Entity{NewEntity(1, 0, 0, 1, 2, 0, 0)}, Integrator: VelocityVerlet{}, Solver: DirectSolver{}, Dt: dt}
+		done := make(chan bool)
+		go func() {
+			s.AdvanceTo(1)
+			done <- true
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Advancing with a step of %v did not return", dt)
+		}
+		if s.Time != 1 || pointsinequal(s.Entities[0].Position, NewPoint(1, 2), 4) {
+			t.Errorf("Advancing with a step of %v reached %v at time %v - expected (1, 2) at time 1", dt, s.Entities[0].Position, s.Time)
+		}
+	}
+}
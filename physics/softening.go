@@ -0,0 +1,91 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+// Kernel selects how gravity is softened at short range
+type Kernel int
+
+const (
+	// NoSoftening is plain Newtonian gravity
+	NoSoftening Kernel = iota
+	// Plummer softening treats each entity as a Plummer sphere, gravity is never exactly Newtonian
+	Plummer
+	// Spline softening uses the Monaghan cubic spline density kernel, gravity is exactly Newtonian beyond the softening length
+	Spline
+)
+
+// Softening describes the kernel and length used to soften gravity between two entities. The zero value is
+// unsoftened Newtonian gravity. Every kernel, including NoSoftening, exerts no force between coincident entities
+type Softening struct {
+	Kernel Kernel
+	Length float64
+}
+
+// String returns the formatted string "Softening{Kernel: ..., Length: ...}"
+func (s Softening) String() string {
+	return fmt.Sprintf("Softening{Kernel: %v, Length: %v}", s.Kernel, s.Length)
+}
+
+// String returns the name of the kernel
+func (k Kernel) String() string {
+	switch k {
+	case NoSoftening:
+		return "None"
+	case Plummer:
+		return "Plummer"
+	case Spline:
+		return "Spline"
+	default:
+		return fmt.Sprintf("Kernel(%d)", int(k))
+	}
+}
+
+// factor returns g such that the acceleration towards a unit mass at the given distance is G * g * displacement,
+// along with the rate of change of g with distance divided by distance used to compute jerk
+func (s Softening) factor(distance float64) (float64, float64) {
+	if distance == 0 {
+		return 0, 0
+	}
+
+	switch {
+	case s.Kernel == Plummer && s.Length > 0:
+		squared := distance*distance + s.Length*s.Length
+		g := 1 / (squared * math.Sqrt(squared))
+		return g, -3 * g / squared
+	case s.Kernel == Spline && distance < s.Length:
+		h := s.Length
+		u := distance / h
+		h3, h5 := h*h*h, h*h*h*h*h
+		if u < 0.5 {
+			return (32.0/3 + u*u*(32*u-38.4)) / h3, (96*u - 76.8) / h5
+		}
+		g := (64.0/3 - 48*u + 38.4*u*u - 32.0/3*u*u*u - 1/(15*u*u*u)) / h3
+		dgdu := -48 + 76.8*u - 32*u*u + 1/(5*u*u*u*u)
+		return g, dgdu / u / h5
+	default:
+		cubed := distance * distance * distance
+		return 1 / cubed, -3 / (cubed * distance * distance)
+	}
+}
+
+// potential returns the gravitational potential of a unit mass at the given distance divided by G, consistent with factor
+func (s Softening) potential(distance float64) float64 {
+	switch {
+	case s.Kernel == Plummer && s.Length > 0:
+		return -1 / math.Sqrt(distance*distance+s.Length*s.Length)
+	case s.Kernel == Spline && distance < s.Length:
+		h := s.Length
+		u := distance / h
+		if u < 0.5 {
+			return (-2.8 + u*u*(16.0/3+u*u*(6.4*u-9.6))) / h
+		}
+		return (-3.2 + 1/(15*u) + u*u*(32.0/3+u*(-16+u*(9.6-32.0/15*u)))) / h
+	case distance == 0:
+		return 0
+	default:
+		return -1 / distance
+	}
+}
@@ -0,0 +1,85 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"math"
+	"testing"
+)
+
+func TestSofteningFactor(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		softening Softening
+		distance  float64
+		expected  float64
+	}{
+		{Softening{}, 0, 0},
+		{Softening{}, 2, 0.125},
+		{Softening{Plummer, 1}, 0, 0},
+		{Softening{Plummer, 1}, 1, 0.3536},
+		{Softening{Plummer, 0}, 2, 0.125},
+		{Softening{Spline, 1}, 0, 0},
+		{Softening{Spline, 1}, 0.25, 8.7667},
+		{Softening{Spline, 1}, 0.5, 5.0667},
+		{Softening{Spline, 1}, 1, 1},
+		{Softening{Spline, 1}, 2, 0.125},
+	}
+
+	for _, c := range cases {
+		g, _ := c.softening.factor(c.distance)
+		if utils.RoundPrecision(g, testprecision) != c.expected {
+			t.Errorf("Computing %v factor at %v = %v - expected %v", c.softening, c.distance, g, c.expected)
+		}
+	}
+}
+
+func TestSofteningPotential(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		softening Softening
+		distance  float64
+		expected  float64
+	}{
+		{Softening{}, 0, 0},
+		{Softening{}, 2, -0.5},
+		{Softening{Plummer, 1}, 0, -1},
+		{Softening{Plummer, 1}, 1, -0.7071},
+		{Softening{Spline, 1}, 0, -2.8},
+		{Softening{Spline, 1}, 1, -1},
+		{Softening{Spline, 1}, 4, -0.25},
+	}
+
+	for _, c := range cases {
+		potential := c.softening.potential(c.distance)
+		if utils.RoundPrecision(potential, testprecision) != c.expected {
+			t.Errorf("Computing %v potential at %v = %v - expected %v", c.softening, c.distance, potential, c.expected)
+		}
+	}
+}
+
+func TestSofteningConsistency(t *testing.T) {
+	t.Parallel()
+	softenings := []Softening{{}, {Plummer, 1}, {Spline, 1}}
+	distances := []float64{0.1, 0.3, 0.5, 0.7, 0.99, 1.5, 3}
+	h := 1e-6
+
+	for _, s := range softenings {
+		for _, r := range distances {
+			// The force factor is the negated slope of the potential over distance
+			g, rate := s.factor(r)
+			slope := (s.potential(r+h) - s.potential(r-h)) / (2 * h)
+			if math.Abs(g*r-slope) > 1e-5*math.Max(1, slope) {
+				t.Errorf("Computing %v at %v: factor %v inconsistent with potential slope %v", s, r, g*r, slope)
+			}
+
+			// The rate is the slope of the force factor over distance
+			gplus, _ := s.factor(r + h)
+			gminus, _ := s.factor(r - h)
+			if derivative := (gplus - gminus) / (2 * h); math.Abs(rate*r-derivative) > 1e-4*math.Max(1, math.Abs(derivative)) {
+				t.Errorf("Computing %v at %v: rate %v inconsistent with factor slope %v", s, r, rate*r, derivative)
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package physics
+
+// Solver computes the gravitational acceleration of every entity in a slice
+type Solver interface {
+	Accelerate(entities []*Entity)
+}
+
+// JerkSolver is a Solver that can also compute the jerk, the time derivative of acceleration, of a subset
+// of the entities in a slice
+type JerkSolver interface {
+	Solver
+	AccelerateWithJerk(active []*Entity, entities []*Entity)
+}
+
+// jerksolverof returns the solver if it can compute jerks, or otherwise direct summation with the same softening,
+// over the nearest images of entities for solvers of a periodic domain
+func jerksolverof(solver Solver) JerkSolver {
+	switch s := solver.(type) {
+	case JerkSolver:
+		return s
+	case PairwiseSolver:
+		return DirectSolver{Softening: s.Softening}
+	case BarnesHutSolver:
+		return DirectSolver{Softening: s.Softening}
+	case FMMSolver:
+		return DirectSolver{Softening: s.Softening}
+	case PMSolver:
+		return MinimumImageSolver{Domain: s.Domain}
+	}
+	return DirectSolver{}
+}
+
+// DirectSolver sums the gravitational acceleration of every pair of entities, softened by Softening. Compensated
+// sums each entity's acceleration with compensated summation, which keeps the pull of light entities next to much
+// heavier ones at some cost in speed
+type DirectSolver struct {
+	Softening   Softening
+	Compensated bool
+}
+
+// Accelerate updates the acceleration of every entity from every other entity in the slice
+func (s DirectSolver) Accelerate(entities []*Entity) {
+	for _, e := range entities {
+		if s.Compensated {
+			e.UpdateCompensatedGravitationalAcceleration(entities, s.Softening)
+		} else {
+			e.UpdateSoftenedGravitationalAcceleration(entities, s.Softening)
+		}
+	}
+}
+
+// AccelerateWithJerk updates the acceleration and jerk of every active entity from every other entity in the slice
+func (s DirectSolver) AccelerateWithJerk(active []*Entity, entities []*Entity) {
+	for _, e := range active {
+		e.UpdateSoftenedGravitationalAccelerationAndJerk(entities, s.Softening)
+	}
+}
+
+// PairwiseSolver sums the gravitational acceleration of every pair of entities like DirectSolver, softened by
+// Softening, but visits each pair once and applies equal and opposite forces to both entities. That halves the
+// distance computations and conserves total momentum to round-off
+type PairwiseSolver struct {
+	Softening Softening
+}
+
+// Accelerate updates the acceleration of every entity from every other entity in the slice
+func (s PairwiseSolver) Accelerate(entities []*Entity) {
+	for _, e := range entities {
+		e.Acceleration = NewVector2D(0, 0)
+	}
+
+	for i, e1 := range entities {
+		for _, e2 := range entities[i+1:] {
+			// Scale the displacement rather than normalizing it so coincident entities exert nothing
+			displacement := e1.Position.DisplacementVector(e2.Position)
+			g, _ := s.Softening.factor(displacement.Length())
+			pull := displacement.Scalarmul(G * g)
+			e1.Acceleration = e1.Acceleration.Add(pull.Scalarmul(e2.Mass))
+			e2.Acceleration = e2.Acceleration.Subtract(pull.Scalarmul(e1.Mass))
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package physics
+
+import "testing"
+
+func TestDirectSolverAccelerate(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		entities []*Entity
+		expected []*Entity
+	}{
+		{[]*Entity{NewEntity(1, 0, 0, 0, 0, 5, 5)}, []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0)}},
+		{[]*Entity{NewEntity(1e12, 0, 0, 0, 0, 0, 0), NewEntity(1e12, 1, 0, 0, 0, 0, 0)}, []*Entity{NewEntity(1e12, 0, 0, 0, 0, 6.67834e14, 0), NewEntity(1e12, 1, 0, 0, 0, -6.67834e14, 0)}},
+		{[]*Entity{NewEntity(1, -1, 0, 0, 0, 0, 0), NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 1, 0, 0, 0, 0, 0)}, []*Entity{NewEntity(1, -1, 0, 0, 0, 834.7925, 0), NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 1, 0, 0, 0, -834.7925, 0)}},
+	}
+
+	for _, c := range cases {
+		DirectSolver{}.Accelerate(c.entities)
+		for i := range c.entities {
+			if accelerationsinequal(c.entities[i], c.expected[i], testprecision) {
+				t.Errorf("Computing direct accelerations: got %v - expected %v", c.entities[i], c.expected[i])
+			}
+		}
+	}
+}
+
+func TestDirectSolverSoftening(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		softening Softening
+		entities  []*Entity
+		expected  []*Entity
+	}{
+		{Softening{}, []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 0, 0, 0, 0, 0, 0)}, []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 0, 0, 0, 0, 0, 0)}},
+		{Softening{Plummer, 1}, []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 1, 0, 0, 0, 0, 0)}, []*Entity{NewEntity(1, 0, 0, 0, 0, 236.115, 0), NewEntity(1, 1, 0, 0, 0, -236.115, 0)}},
+		{Softening{Spline, 10}, []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 20, 0, 0, 0, 0, 0)}, []*Entity{NewEntity(1, 0, 0, 0, 0, 1.6696, 0), NewEntity(1, 20, 0, 0, 0, -1.6696, 0)}},
+	}
+
+	for _, c := range cases {
+		DirectSolver{Softening: c.softening}.Accelerate(c.entities)
+		for i := range c.entities {
+			if accelerationsinequal(c.entities[i], c.expected[i], testprecision) {
+				t.Errorf("Computing direct accelerations with %v: got %v - expected %v", c.softening, c.entities[i], c.expected[i])
+			}
+		}
+	}
+}
+
+func TestPairwiseSolverAccuracy(t *testing.T) {
+	t.Parallel()
+	for _, softening := range []Softening{{}, {Plummer, 3}, {Spline, 10}} {
+		entities := randomentities(80, 300, 9)
+		if worst := accelerationerror(entities, PairwiseSolver{Softening: softening}, softening); worst > 1e-12 {
+			t.Errorf("Computing pairwise accelerations with %v: error %v - expected at most 1e-12", softening, worst)
+		}
+	}
+}
+
+func TestPairwiseSolverMomentum(t *testing.T) {
+	t.Parallel()
+	entities := randomentities(50, 300, 10)
+	for i, e := range entities {
+		e.Velocity = NewVector2D(float64(i%7)-3, float64(i%5)-2)
+	}
+	momentum := func() (Vector2D, float64) {
+		total, scale := NewVector2D(0, 0), 0.0
+		for _, e := range entities {
+			total = total.Add(e.Velocity.Scalarmul(e.Mass))
+			scale += e.Velocity.Scalarmul(e.Mass).Length()
+		}
+		return total, scale
+	}
+	start, scale := momentum()
+
+	// The forces on every entity cancel out
+	PairwiseSolver{}.Accelerate(entities)
+	force, forcescale := NewVector2D(0, 0), 0.0
+	for _, e := range entities {
+		force = force.Add(e.Acceleration.Scalarmul(e.Mass))
+		forcescale += e.Acceleration.Scalarmul(e.Mass).Length()
+	}
+	if force.Length() > 1e-14*forcescale {
+		t.Errorf("Computing pairwise forces: net force %v - expected none", force)
+	}
+
+	// So total momentum holds still as the entities move, softened so close encounters do not swamp it with the
+	// round-off of adding huge kicks to velocities
+	solver := PairwiseSolver{Softening: Softening{Plummer, 5}}
+	for i := 0; i < 500; i++ {
+		Leapfrog{}.Step(entities, solver, 0.01)
+	}
+	if end, _ := momentum(); end.Subtract(start).Length() > 1e-13*scale {
+		t.Errorf("Advancing with pairwise forces: momentum went from %v to %v - expected no change", start, end)
+	}
+}
+
+// balanced returns a light probe at the origin between two stars of the given mass whose pulls cancel, with many
+// light entities around at random between the stars in the slice, like the planets of the README examples scaled up
+// in count and mass ratio. The probe's acceleration is the small remainder of the light entities' pulls
+func balanced(starmass float64) ([]*Entity, *Entity) {
+	probe := NewEntity(1, 0, 0, 0, 0, 0, 0)
+	entities := []*Entity{NewEntity(starmass, -100, 0, 0, 0, 0, 0)}
+	entities = append(entities, randomentities(400, 300, 13)...)
+	return append(entities, NewEntity(starmass, 100, 0, 0, 0, 0, 0), probe), probe
+}
+
+// exactaccelerations returns the accelerations of the entities with the contributions summed exactly, so only the
+// rounding of each contribution remains
+func exactaccelerations(entities []*Entity, softening Softening) []Vector2D {
+	accelerations := make([]Vector2D, len(entities))
+	for n, e1 := range entities {
+		xs, ys := []float64{}, []float64{}
+		for _, e2 := range entities {
+			if e1 == e2 {
+				continue
+			}
+			displacement := e1.Position.DisplacementVector(e2.Position)
+			g, _ := softening.factor(displacement.Length())
+			contribution := displacement.Scalarmul(G * e2.Mass * g)
+			xs, ys = append(xs, contribution.X), append(ys, contribution.Y)
+		}
+		accelerations[n] = NewVector2D(exactsum(xs), exactsum(ys))
+	}
+	return accelerations
+}
+
+func TestDirectSolverCompensated(t *testing.T) {
+	t.Parallel()
+	for _, starmass := range []float64{1e6, 1e9, 1e12} {
+		entities, probe := balanced(starmass)
+		exact := exactaccelerations(entities, Softening{})[len(entities)-1]
+
+		// The summation error of the probe's acceleration relative to its size
+		relative := func(solver Solver) float64 {
+			solver.Accelerate(entities)
+			return probe.Acceleration.Subtract(exact).Length() / exact.Length()
+		}
+		plain := relative(DirectSolver{})
+		compensated := relative(DirectSolver{Compensated: true})
+		parallel := relative(ParallelSolver{Workers: 4, Compensated: true})
+
+		if compensated > 1e-15 || parallel != compensated {
+			t.Errorf("Accelerating between stars of mass %v with compensation: error %v, parallel %v - expected at most 1e-15", starmass, compensated, parallel)
+		}
+		if plain < 1e-13 {
+			t.Errorf("Accelerating between stars of mass %v: plain error %v - expected the light pulls lost to rounding", starmass, plain)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package physics
+
+import "math"
+
+// Sum accumulates a running total of floating point values. A Compensated sum uses Neumaier's variant of Kahan
+// summation, carrying the low order bits each addition rounds away in a separate compensation term, so small values
+// added to a large total are not lost. An uncompensated sum adds values exactly as += would
+type Sum struct {
+	Compensated bool
+
+	sum          float64
+	compensation float64
+}
+
+// Add adds the value to the total
+func (s *Sum) Add(value float64) {
+	if !s.Compensated {
+		s.sum += value
+		return
+	}
+
+	// Whichever of the total and the value is smaller in magnitude loses bits to rounding, recover them
+	total := s.sum + value
+	if math.Abs(s.sum) >= math.Abs(value) {
+		s.compensation += (s.sum - total) + value
+	} else {
+		s.compensation += (value - total) + s.sum
+	}
+	s.sum = total
+}
+
+// Value returns the total
+func (s Sum) Value() float64 {
+	return s.sum + s.compensation
+}
+
+// VectorSum accumulates a running total of vectors, summing each component with a Sum
+type VectorSum struct {
+	X Sum
+	Y Sum
+}
+
+// NewVectorSum returns a new zero VectorSum, compensated or not
+func NewVectorSum(compensated bool) VectorSum {
+	return VectorSum{X: Sum{Compensated: compensated}, Y: Sum{Compensated: compensated}}
+}
+
+// Add adds the vector to the total
+func (s *VectorSum) Add(v Vector2D) {
+	s.X.Add(v.X)
+	s.Y.Add(v.Y)
+}
+
+// Value returns the total
+func (s VectorSum) Value() Vector2D {
+	return NewVector2D(s.X.Value(), s.Y.Value())
+}
@@ -0,0 +1,66 @@
+package physics
+
+import (
+	"math/big"
+	"testing"
+)
+
+// exactsum returns the sum of the values rounded once to the nearest float64
+func exactsum(values []float64) float64 {
+	sum := new(big.Float).SetPrec(2048)
+	for _, value := range values {
+		sum.Add(sum, new(big.Float).SetFloat64(value))
+	}
+	total, _ := sum.Float64()
+	return total
+}
+
+func TestSum(t *testing.T) {
+	t.Parallel()
+	many := []float64{1e16}
+	for i := 0; i < 1000; i++ {
+		many = append(many, 0.5)
+	}
+	cases := []struct {
+		values []float64
+		plain  float64
+	}{
+		{[]float64{}, 0},
+		{[]float64{1, 2, 3.5}, 6.5},
+		// Every small value is below half a unit in the last place of the total and rounds away
+		{many, 1e16},
+		// The large values cancel, leaving only what the plain sum lost
+		{[]float64{1, 1e100, 1, -1e100}, 0},
+		{[]float64{0.1, 0.2, 0.3, -0.6}, 1.1102230246251565e-16},
+	}
+
+	for _, c := range cases {
+		plain, compensated := Sum{}, Sum{Compensated: true}
+		for _, value := range c.values {
+			plain.Add(value)
+			compensated.Add(value)
+		}
+		if plain.Value() != c.plain {
+			t.Errorf("Summing %v values: got %v - expected %v", len(c.values), plain.Value(), c.plain)
+		}
+		if expected := exactsum(c.values); compensated.Value() != expected {
+			t.Errorf("Summing %v values with compensation: got %v - expected %v", len(c.values), compensated.Value(), expected)
+		}
+	}
+}
+
+func TestVectorSum(t *testing.T) {
+	t.Parallel()
+	values := []Vector2D{NewVector2D(1, -1), NewVector2D(1e100, 3), NewVector2D(1, 1e17), NewVector2D(-1e100, -1e17)}
+	plain, compensated := NewVectorSum(false), NewVectorSum(true)
+	for _, v := range values {
+		plain.Add(v)
+		compensated.Add(v)
+	}
+	if expected := NewVector2D(0, 0); plain.Value() != expected {
+		t.Errorf("Summing %v: got %v - expected %v", values, plain.Value(), expected)
+	}
+	if expected := NewVector2D(2, 2); compensated.Value() != expected {
+		t.Errorf("Summing %v with compensation: got %v - expected %v", values, compensated.Value(), expected)
+	}
+}
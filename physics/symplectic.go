@@ -0,0 +1,100 @@
+package physics
+
+import "math"
+
+// ForestRuth is the fourth order Forest-Ruth symplectic integrator, a drift
+// first composition of three leapfrog steps
+type ForestRuth struct{}
+
+// Step advances the entities by dt with the Forest-Ruth scheme
+func (i ForestRuth) Step(entities []*Entity, solver Solver, dt float64) {
+	forestruth.step(entities, solver, dt)
+}
+
+// Yoshida4 is Yoshida's fourth order symplectic integrator, a kick first
+// composition of three leapfrog steps
+type Yoshida4 struct{}
+
+// Step advances the entities by dt with Yoshida's fourth order scheme
+func (i Yoshida4) Step(entities []*Entity, solver Solver, dt float64) {
+	yoshida4.step(entities, solver, dt)
+}
+
+// Yoshida6 is Yoshida's sixth order symplectic integrator, a kick first
+// composition of seven leapfrog steps
+type Yoshida6 struct{}
+
+// Step advances the entities by dt with Yoshida's sixth order scheme
+func (i Yoshida6) Step(entities []*Entity, solver Solver, dt float64) {
+	yoshida6.step(entities, solver, dt)
+}
+
+// Fourth order triple jump weights
+var triplejump = []float64{
+	1 / (2 - math.Cbrt(2)),
+	-math.Cbrt(2) / (2 - math.Cbrt(2)),
+	1 / (2 - math.Cbrt(2)),
+}
+
+// Yoshida's sixth order weights, solution A
+var yoshidaweights = []float64{
+	0.784513610477560,
+	0.235573213359357,
+	-1.17767998417887,
+	1 - 2*(0.784513610477560+0.235573213359357-1.17767998417887),
+	-1.17767998417887,
+	0.235573213359357,
+	0.784513610477560,
+}
+
+var forestruth = driftfirst(triplejump)
+var yoshida4 = kickfirst(triplejump)
+var yoshida6 = kickfirst(yoshidaweights)
+
+// splitting is a symplectic scheme of alternating drifts and kicks, starting
+// and ending with a drift, each scaled by its coefficient times the step size
+type splitting struct {
+	drifts []float64
+	kicks  []float64
+}
+
+// driftfirst returns the splitting of a composition of drift-kick-drift
+// leapfrog steps with the given weights, merging adjacent drifts
+func driftfirst(weights []float64) splitting {
+	s := splitting{drifts: make([]float64, len(weights)+1), kicks: make([]float64, len(weights))}
+	for n, w := range weights {
+		s.drifts[n] += w / 2
+		s.drifts[n+1] += w / 2
+		s.kicks[n] = w
+	}
+	return s
+}
+
+// kickfirst returns the splitting of a composition of kick-drift-kick
+// leapfrog steps with the given weights, merging adjacent kicks
+func kickfirst(weights []float64) splitting {
+	s := splitting{drifts: make([]float64, len(weights)+2), kicks: make([]float64, len(weights)+1)}
+	for n, w := range weights {
+		s.kicks[n] += w / 2
+		s.kicks[n+1] += w / 2
+		s.drifts[n+1] = w
+	}
+	return s
+}
+
+// step advances the entities by dt through the drifts and kicks of the splitting,
+// skipping zero drifts and the force evaluations of zero kicks
+func (s splitting) step(entities []*Entity, solver Solver, dt float64) {
+	for n, k := range s.kicks {
+		if s.drifts[n] != 0 {
+			drift(entities, s.drifts[n]*dt)
+		}
+		if k != 0 {
+			solver.Accelerate(entities)
+			kick(entities, k*dt)
+		}
+	}
+	if last := s.drifts[len(s.drifts)-1]; last != 0 {
+		drift(entities, last*dt)
+	}
+}
@@ -0,0 +1,64 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// solarsystem returns the README's several planets orbiting a star example
+func solarsystem() []*Entity {
+	return []*Entity{
+		NewEntity(1000, 0, 0, 0, 0, 0, 0),
+		NewEntity(10, 200, 0, 0, 60, 0, 0),
+		NewEntity(2, 0, 100, -80, 0, 0, 0),
+		NewEntity(3, -150, 0, 0, 65, 0, 0),
+	}
+}
+
+// totalenergy returns the kinetic plus gravitational potential energy of the entities
+func totalenergy(entities []*Entity) float64 {
+	energy := 0.0
+	for i, e1 := range entities {
+		energy += e1.Mass * e1.Velocity.Dotproduct(e1.Velocity) / 2
+		for _, e2 := range entities[i+1:] {
+			energy -= G * e1.Mass * e2.Mass / e1.Distance(e2)
+		}
+	}
+	return energy
+}
+
+func TestSymplecticEnergyBounded(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		integrator Integrator
+		tolerance  float64
+	}{
+		{ForestRuth{}, 1e-8},
+		{Yoshida4{}, 1e-8},
+		{Yoshida6{}, 1e-10},
+	}
+
+	for _, c := range cases {
+		entities := solarsystem()
+		initial := totalenergy(entities)
+		steps := 100000
+		firsthalf, secondhalf := 0.0, 0.0
+		for i := 0; i < steps; i++ {
+			c.integrator.Step(entities, DirectSolver{}, 0.01)
+			drift := math.Abs((totalenergy(entities) - initial) / initial)
+			if i < steps/2 {
+				firsthalf = math.Max(firsthalf, drift)
+			} else {
+				secondhalf = math.Max(secondhalf, drift)
+			}
+		}
+		if secondhalf > c.tolerance {
+			t.Errorf("Integrating %v steps with %T drifted energy by %v - expected at most %v", steps, c.integrator, secondhalf, c.tolerance)
+		}
+
+		// A secular drift would keep growing, a bounded error oscillates at the same amplitude
+		if secondhalf > 2*firsthalf {
+			t.Errorf("Integrating %v steps with %T grew energy error from %v to %v", steps, c.integrator, firsthalf, secondhalf)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package physics
+
+import "math"
+
+// System holds the state of a set of entities as a struct of arrays, one contiguous slice per component, so the
+// force and integration kernels stream through memory instead of chasing a pointer per entity. Entity i is made up
+// of element i of every slice. The components may be of any floating point type, float32 halving the memory the
+// kernels stream through at the cost of precision
+type SystemOf[T Float] struct {
+	Mass []T
+	X    []T
+	Y    []T
+	VX   []T
+	VY   []T
+	AX   []T
+	AY   []T
+}
+
+// System is the double precision struct of arrays layout
+type System = SystemOf[float64]
+
+// NewSystem returns a new System holding a copy of the state of the entities
+func NewSystem(entities []*Entity) *System {
+	return NewSystemOf[float64](entities)
+}
+
+// NewSystemOf returns a new SystemOf holding a copy of the state of the entities, rounded to its precision
+func NewSystemOf[T Float](entities []*Entity) *SystemOf[T] {
+	n := len(entities)
+	s := &SystemOf[T]{
+		Mass: make([]T, n),
+		X:    make([]T, n),
+		Y:    make([]T, n),
+		VX:   make([]T, n),
+		VY:   make([]T, n),
+		AX:   make([]T, n),
+		AY:   make([]T, n),
+	}
+	for i, e := range entities {
+		s.Mass[i] = T(e.Mass)
+		s.X[i], s.Y[i] = T(e.Position.X), T(e.Position.Y)
+		s.VX[i], s.VY[i] = T(e.Velocity.X), T(e.Velocity.Y)
+		s.AX[i], s.AY[i] = T(e.Acceleration.X), T(e.Acceleration.Y)
+	}
+	return s
+}
+
+// Len returns the number of entities in the system
+func (s *SystemOf[T]) Len() int {
+	return len(s.Mass)
+}
+
+// Entities returns a new slice of entities holding a copy of the state of the system
+func (s *SystemOf[T]) Entities() []*Entity {
+	entities := make([]*Entity, s.Len())
+	for i := range entities {
+		entities[i] = NewEntity(float64(s.Mass[i]), float64(s.X[i]), float64(s.Y[i]), float64(s.VX[i]), float64(s.VY[i]), float64(s.AX[i]), float64(s.AY[i]))
+	}
+	return entities
+}
+
+// Accelerate updates the acceleration of every entity in the system from every other entity, softened by the given
+// softening. The sums are formed in the same order as DirectSolver so both layouts give identical results
+func (s *SystemOf[T]) Accelerate(softening Softening) {
+	for i := range s.Mass {
+		var ax, ay T
+		for j := range s.Mass {
+			if i == j {
+				continue
+			}
+			dx, dy := s.X[j]-s.X[i], s.Y[j]-s.Y[i]
+			g, _ := softening.factor(math.Sqrt(float64(dx*dx + dy*dy)))
+			scale := T(G) * s.Mass[j] * T(g)
+			ax += dx * scale
+			ay += dy * scale
+		}
+		s.AX[i], s.AY[i] = ax, ay
+	}
+}
+
+// Kick advances the velocity of every entity in the system by its acceleration over dt
+func (s *SystemOf[T]) Kick(dt float64) {
+	for i := range s.VX {
+		s.VX[i] += s.AX[i] * T(dt)
+		s.VY[i] += s.AY[i] * T(dt)
+	}
+}
+
+// Drift advances the position of every entity in the system by its velocity over dt
+func (s *SystemOf[T]) Drift(dt float64) {
+	for i := range s.X {
+		s.X[i] += s.VX[i] * T(dt)
+		s.Y[i] += s.VY[i] * T(dt)
+	}
+}
+
+// Leapfrog advances the system by dt with a half kick, a full drift and a half kick like the Leapfrog integrator
+func (s *SystemOf[T]) Leapfrog(dt float64, softening Softening) {
+	s.Accelerate(softening)
+	s.Kick(dt / 2)
+	s.Drift(dt)
+	s.Accelerate(softening)
+	s.Kick(dt / 2)
+}
+
+// AcceleratePairwise updates the acceleration of every entity in the system like Accelerate, but visits each pair
+// once and applies equal and opposite forces to both entities like PairwiseSolver
+func (s *SystemOf[T]) AcceleratePairwise(softening Softening) {
+	for i := range s.AX {
+		s.AX[i], s.AY[i] = 0, 0
+	}
+
+	for i := range s.Mass {
+		for j := i + 1; j < len(s.Mass); j++ {
+			dx, dy := s.X[j]-s.X[i], s.Y[j]-s.Y[i]
+			g, _ := softening.factor(math.Sqrt(float64(dx*dx + dy*dy)))
+			px, py := dx*T(G)*T(g), dy*T(G)*T(g)
+			s.AX[i] += px * s.Mass[j]
+			s.AY[i] += py * s.Mass[j]
+			s.AX[j] -= px * s.Mass[i]
+			s.AY[j] -= py * s.Mass[i]
+		}
+	}
+}
+
+// DirectSolverOf sums the gravitational acceleration of every pair of entities like DirectSolver, softened by
+// Softening, but in the precision of T, so with float64 it gives the same results as DirectSolver. It is meant for
+// measuring how accurate a precision is rather than for speed: each call allocates a fresh SystemOf and copies the
+// entities into it, so simulations that want the speed of a narrower type should step a SystemOf directly
+type DirectSolverOf[T Float] struct {
+	Softening Softening
+}
+
+// Accelerate updates the acceleration of every entity from every other entity in the slice
+func (s DirectSolverOf[T]) Accelerate(entities []*Entity) {
+	system := NewSystemOf[T](entities)
+	system.Accelerate(s.Softening)
+	for i, e := range entities {
+		e.Acceleration = NewVector2D(float64(system.AX[i]), float64(system.AY[i]))
+	}
+}
@@ -0,0 +1,169 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSystemConversion(t *testing.T) {
+	t.Parallel()
+	entities := []*Entity{NewEntity(1, 2, 3, 4, 5, 6, 7), NewEntity(8, -9, 10, -11, 12, -13, 14)}
+	s := NewSystem(entities)
+	if s.Len() != len(entities) {
+		t.Errorf("Converting %v entities to a system: got %v entities", len(entities), s.Len())
+	}
+
+	expected := &System{
+		Mass: []float64{1, 8},
+		X:    []float64{2, -9},
+		Y:    []float64{3, 10},
+		VX:   []float64{4, -11},
+		VY:   []float64{5, 12},
+		AX:   []float64{6, -13},
+		AY:   []float64{7, 14},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("Converting %v to a system: got %v - expected %v", entities, s, expected)
+	}
+
+	if converted := s.Entities(); !reflect.DeepEqual(converted, entities) {
+		t.Errorf("Converting %v back to entities: got %v - expected %v", s, converted, entities)
+	}
+}
+
+func TestSystemAccelerate(t *testing.T) {
+	t.Parallel()
+	for _, softening := range []Softening{{}, {Plummer, 3}, {Spline, 10}} {
+		entities := randomentities(60, 300, 8)
+		s := NewSystem(entities)
+		DirectSolver{Softening: softening}.Accelerate(entities)
+		s.Accelerate(softening)
+
+		for i, e := range entities {
+			if s.AX[i] != e.Acceleration.X || s.AY[i] != e.Acceleration.Y {
+				t.Errorf("Computing system acceleration with %v: entity %v got (%v, %v) - expected %v", softening, i, s.AX[i], s.AY[i], e.Acceleration)
+				break
+			}
+		}
+	}
+}
+
+func TestSystemLeapfrog(t *testing.T) {
+	t.Parallel()
+	entities, _ := circularorbit(100)
+	s := NewSystem(entities)
+	for i := 0; i < 100; i++ {
+		Leapfrog{}.Step(entities, DirectSolver{}, 0.01)
+		s.Leapfrog(0.01, Softening{})
+	}
+
+	if converted := s.Entities(); !reflect.DeepEqual(converted, entities) {
+		t.Errorf("Advancing a system with leapfrog: got %v - expected %v", converted, entities)
+	}
+}
+
+func BenchmarkLayouts(b *testing.B) {
+	for _, count := range []int{100, 1000, 3000} {
+		entities := randomentities(count, 300, 3)
+		s := NewSystem(entities)
+		b.Run(fmt.Sprintf("Entities/Accelerate/%v", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				DirectSolver{}.Accelerate(entities)
+			}
+		})
+		b.Run(fmt.Sprintf("System/Accelerate/%v", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Accelerate(Softening{})
+			}
+		})
+		b.Run(fmt.Sprintf("Entities/Leapfrog/%v", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Leapfrog{}.Step(entities, DirectSolver{}, 0.01)
+			}
+		})
+		b.Run(fmt.Sprintf("System/Leapfrog/%v", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.Leapfrog(0.01, Softening{})
+			}
+		})
+	}
+}
+
+func TestSystemAcceleratePairwise(t *testing.T) {
+	t.Parallel()
+	softening := Softening{Plummer, 3}
+	s := NewSystem(randomentities(60, 300, 11))
+	s.Accelerate(softening)
+	ax, ay := append([]float64{}, s.AX...), append([]float64{}, s.AY...)
+	s.AcceleratePairwise(softening)
+
+	netx, nety, scale := 0.0, 0.0, 0.0
+	for i := range s.Mass {
+		if math.Abs(s.AX[i]-ax[i]) > 1e-12*math.Abs(ax[i]) || math.Abs(s.AY[i]-ay[i]) > 1e-12*math.Abs(ay[i]) {
+			t.Errorf("Computing pairwise system acceleration: entity %v got (%v, %v) - expected (%v, %v)", i, s.AX[i], s.AY[i], ax[i], ay[i])
+		}
+		netx += s.Mass[i] * s.AX[i]
+		nety += s.Mass[i] * s.AY[i]
+		scale += s.Mass[i] * math.Hypot(s.AX[i], s.AY[i])
+	}
+	if math.Hypot(netx, nety) > 1e-14*scale {
+		t.Errorf("Computing pairwise system forces: net force (%v, %v) - expected none", netx, nety)
+	}
+}
+
+func TestDirectSolverOf(t *testing.T) {
+	t.Parallel()
+	softening := Softening{Plummer, 3}
+	entities := randomentities(60, 300, 5)
+	DirectSolver{Softening: softening}.Accelerate(entities)
+	expected := make([]Vector2D, len(entities))
+	for n, e := range entities {
+		expected[n] = e.Acceleration
+	}
+
+	DirectSolverOf[float64]{Softening: softening}.Accelerate(entities)
+	for n, e := range entities {
+		if e.Acceleration != expected[n] {
+			t.Errorf("Computing float64 acceleration of entity %v got %v - expected %v", n, e.Acceleration, expected[n])
+			break
+		}
+	}
+
+	if err := accelerationerror(entities, DirectSolverOf[float32]{Softening: softening}, softening); err > 1e-5 {
+		t.Errorf("Computing float32 accelerations: error %v - expected below 1e-5", err)
+	}
+}
+
+func TestSystemOfLeapfrog(t *testing.T) {
+	t.Parallel()
+	entities, period := circularorbit(100)
+	s := NewSystemOf[float32](entities)
+	steps := 1000
+	for i := 0; i < steps; i++ {
+		s.Leapfrog(period/float64(steps), Softening{})
+	}
+
+	planet := s.Entities()[1]
+	if miss := planet.Position.Distance(entities[1].Position) / 100; miss > 1e-3 {
+		t.Errorf("Advancing a float32 system a period: got %v - expected %v", planet.Position, entities[1].Position)
+	}
+}
+
+func BenchmarkPrecisions(b *testing.B) {
+	for _, count := range []int{100, 1000, 3000} {
+		entities := randomentities(count, 300, 3)
+		s64, s32 := NewSystemOf[float64](entities), NewSystemOf[float32](entities)
+		b.Run(fmt.Sprintf("float64/%v", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s64.Leapfrog(0.01, Softening{})
+			}
+		})
+		b.Run(fmt.Sprintf("float32/%v", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s32.Leapfrog(0.01, Softening{})
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Gravitational constant in SI units, cubic metres per kilogram per square second
+const GSI float64 = 6.67430e-11
+
+// Units is a system of units for lengths, masses and times, given as how many metres, kilograms and seconds make
+// up one unit of each, along with the gravitational constant measured in them and the symbols to label values with.
+// The zero value is the playground units the package has always used, with G as the gravitational constant
+type Units struct {
+	Name string
+
+	Metres    float64
+	Kilograms float64
+	Seconds   float64
+	G         float64
+
+	LengthSymbol string
+	MassSymbol   string
+	TimeSymbol   string
+}
+
+// NewUnits returns new Units from the size of each unit in SI units, with the gravitational constant converted
+// from its SI value
+func NewUnits(name string, metres float64, kilograms float64, seconds float64, lengthsymbol string, masssymbol string, timesymbol string) Units {
+	return Units{
+		Name:         name,
+		Metres:       metres,
+		Kilograms:    kilograms,
+		Seconds:      seconds,
+		G:            GSI * kilograms * seconds * seconds / (metres * metres * metres),
+		LengthSymbol: lengthsymbol,
+		MassSymbol:   masssymbol,
+		TimeSymbol:   timesymbol,
+	}
+}
+
+var (
+	// Playground units keep the made up gravity of G, which makes the unit of mass about 10^13 kilograms, with
+	// lengths in pixels of a metre each and times in seconds
+	Playground = Units{Name: "Playground", Metres: 1, Kilograms: G / GSI, Seconds: 1, G: G, LengthSymbol: "px", MassSymbol: "M", TimeSymbol: "s"}
+
+	// SI units are metres, kilograms and seconds
+	SI = NewUnits("SI", 1, 1, 1, "m", "kg", "s")
+
+	// Astronomical units are astronomical units, solar masses and Julian years, in which G is close to 4 pi^2
+	Astronomical = NewUnits("Astronomical", 1.495978707e11, 1.98847e30, 3.15576e7, "AU", "M☉", "yr")
+)
+
+// Dimension is the powers of length, mass and time a quantity is measured in
+type Dimension struct {
+	Length int
+	Mass   int
+	Time   int
+}
+
+// Dimensions of the quantities a simulation deals in
+var Dimensions = struct {
+	Length          Dimension
+	Mass            Dimension
+	Time            Dimension
+	Velocity        Dimension
+	Acceleration    Dimension
+	Jerk            Dimension
+	Momentum        Dimension
+	AngularMomentum Dimension
+	Energy          Dimension
+	SpecificEnergy  Dimension
+}{
+	Length:          Dimension{Length: 1},
+	Mass:            Dimension{Mass: 1},
+	Time:            Dimension{Time: 1},
+	Velocity:        Dimension{Length: 1, Time: -1},
+	Acceleration:    Dimension{Length: 1, Time: -2},
+	Jerk:            Dimension{Length: 1, Time: -3},
+	Momentum:        Dimension{Length: 1, Mass: 1, Time: -1},
+	AngularMomentum: Dimension{Length: 2, Mass: 1, Time: -1},
+	Energy:          Dimension{Length: 2, Mass: 1, Time: -2},
+	SpecificEnergy:  Dimension{Length: 2, Time: -2},
+}
+
+// gravity returns the gravitational constant in the units, G for the zero value
+func (u Units) gravity() float64 {
+	if u.G == 0 {
+		return G
+	}
+	return u.G
+}
+
+// scale returns how many SI units of the dimension one unit of it is, treating the zero value as Playground
+func (u Units) scale(d Dimension) float64 {
+	if u.Metres == 0 && u.Kilograms == 0 && u.Seconds == 0 {
+		u = Playground
+	}
+	return math.Pow(u.Metres, float64(d.Length)) * math.Pow(u.Kilograms, float64(d.Mass)) * math.Pow(u.Seconds, float64(d.Time))
+}
+
+// Convert returns a value of the given dimension measured in these units as measured in the target units
+func (u Units) Convert(value float64, d Dimension, to Units) float64 {
+	return value * (u.scale(d) / to.scale(d))
+}
+
+// ConvertEntity returns a new entity that is the entity measured in these units as measured in the target units
+func (u Units) ConvertEntity(e *Entity, to Units) *Entity {
+	length, velocity, acceleration := u.Convert(1, Dimensions.Length, to), u.Convert(1, Dimensions.Velocity, to), u.Convert(1, Dimensions.Acceleration, to)
+	return &Entity{
+		Mass:         u.Convert(e.Mass, Dimensions.Mass, to),
+		Radius:       e.Radius * length,
+		Position:     NewPoint(e.Position.X*length, e.Position.Y*length),
+		Velocity:     e.Velocity.Scalarmul(velocity),
+		Acceleration: e.Acceleration.Scalarmul(acceleration),
+		Jerk:         e.Jerk.Scalarmul(u.Convert(1, Dimensions.Jerk, to)),
+	}
+}
+
+// ConvertEntities returns new entities that are the entities measured in these units as measured in the target
+// units
+func (u Units) ConvertEntities(entities []*Entity, to Units) []*Entity {
+	converted := make([]*Entity, len(entities))
+	for n, e := range entities {
+		converted[n] = u.ConvertEntity(e, to)
+	}
+	return converted
+}
+
+// Symbol returns the symbol of the unit of the dimension, such as "m/s²" for acceleration in SI units
+func (u Units) Symbol(d Dimension) string {
+	if u.LengthSymbol == "" && u.MassSymbol == "" && u.TimeSymbol == "" {
+		u = Playground
+	}
+	numerator, denominator := []string{}, []string{}
+	for _, unit := range []struct {
+		symbol string
+		power  int
+	}{{u.MassSymbol, d.Mass}, {u.LengthSymbol, d.Length}, {u.TimeSymbol, d.Time}} {
+		switch {
+		case unit.power > 0:
+			numerator = append(numerator, unit.symbol+superscript(unit.power))
+		case unit.power < 0:
+			denominator = append(denominator, unit.symbol+superscript(-unit.power))
+		}
+	}
+
+	symbol := strings.Join(numerator, "·")
+	if symbol == "" && len(denominator) > 0 {
+		symbol = "1"
+	}
+	if len(denominator) > 0 {
+		symbol += "/" + strings.Join(denominator, "·")
+	}
+	return symbol
+}
+
+// Format returns the value followed by the symbol of its unit
+func (u Units) Format(value float64, d Dimension) string {
+	return fmt.Sprintf("%.4g %v", value, u.Symbol(d))
+}
+
+// String returns the name of the units
+func (u Units) String() string {
+	if u.Name == "" {
+		return Playground.Name
+	}
+	return u.Name
+}
+
+// superscript returns the power as superscript digits, or nothing for a power of one
+func superscript(power int) string {
+	if power == 1 {
+		return ""
+	}
+	digits := []rune("⁰¹²³⁴⁵⁶⁷⁸⁹")
+	text := ""
+	for _, digit := range fmt.Sprint(power) {
+		text += string(digits[digit-'0'])
+	}
+	return text
+}
+
+// gravitysolver scales the accelerations and jerks of a solver working with G to those of the gravitational
+// constant of some other units, since both are proportional to it
+type gravitysolver struct {
+	solver Solver
+	scale  float64
+}
+
+// solverin returns the solver with its gravity scaled to the gravitational constant of the units, or the solver
+// itself if that is G
+func solverin(solver Solver, units Units) Solver {
+	if units.gravity() == G {
+		return solver
+	}
+	return gravitysolver{solver: solver, scale: units.gravity() / G}
+}
+
+// Accelerate updates the acceleration of every entity from every other entity in the slice
+func (s gravitysolver) Accelerate(entities []*Entity) {
+	s.solver.Accelerate(entities)
+	for _, e := range entities {
+		e.Acceleration = e.Acceleration.Scalarmul(s.scale)
+	}
+}
+
+// AccelerateWithJerk updates the acceleration and jerk of every active entity from every other entity in the
+// slice, falling back like the Hermite integrators if the solver cannot compute jerks
+func (s gravitysolver) AccelerateWithJerk(active []*Entity, entities []*Entity) {
+	jerksolverof(s.solver).AccelerateWithJerk(active, entities)
+	for _, e := range active {
+		e.Acceleration = e.Acceleration.Scalarmul(s.scale)
+		e.Jerk = e.Jerk.Scalarmul(s.scale)
+	}
+}
@@ -0,0 +1,174 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnitsGravity(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		units    Units
+		expected float64
+	}{
+		{Units{}, G},
+		{Playground, G},
+		{SI, GSI},
+		// Kepler's third law in years and astronomical units for a solar mass
+		{Astronomical, 4 * math.Pi * math.Pi},
+	}
+
+	for _, c := range cases {
+		if g := c.units.gravity(); math.Abs(g-c.expected) > 1e-3*c.expected {
+			t.Errorf("Computing G in %v units = %v - expected %v", c.units, g, c.expected)
+		}
+	}
+}
+
+func TestUnitsSolverJerk(t *testing.T) {
+	t.Parallel()
+
+	// Scaling gravity to other units keeps the softening of a solver that cannot compute jerks
+	softening := Softening{Plummer, 5}
+	entities := []*Entity{NewEntity(1, 0, 0, 0, 1, 0, 0), NewEntity(1, 2, 0, 0, -1, 0, 0)}
+	expected := []*Entity{NewEntity(1, 0, 0, 0, 1, 0, 0), NewEntity(1, 2, 0, 0, -1, 0, 0)}
+	solverin(BarnesHutSolver{Softening: softening}, SI).(JerkSolver).AccelerateWithJerk(entities, entities)
+	DirectSolver{Softening: softening}.AccelerateWithJerk(expected, expected)
+	for n, e := range entities {
+		scaled := expected[n].Jerk.Scalarmul(GSI / G)
+		if math.Abs(e.Jerk.X-scaled.X) > 1e-12*math.Abs(scaled.X) || math.Abs(e.Jerk.Y-scaled.Y) > 1e-12*math.Abs(scaled.Y) {
+			t.Errorf("Computing softened jerk %v in SI units: got %v - expected %v", n, e.Jerk, scaled)
+		}
+	}
+}
+
+func TestUnitsConvert(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		value     float64
+		dimension Dimension
+		from      Units
+		to        Units
+		expected  float64
+	}{
+		{1, Dimensions.Length, Astronomical, SI, 1.495978707e11},
+		{1, Dimensions.Mass, Astronomical, SI, 1.98847e30},
+		{1, Dimensions.Time, Astronomical, SI, 3.15576e7},
+		{2 * math.Pi, Dimensions.Velocity, Astronomical, SI, 29785.2},
+		{9.81, Dimensions.Acceleration, SI, Astronomical, 65305.77},
+		{1, Dimensions.Mass, Playground, SI, G / GSI},
+		{1, Dimensions.Energy, Units{}, SI, G / GSI},
+	}
+
+	for _, c := range cases {
+		converted := c.from.Convert(c.value, c.dimension, c.to)
+		if math.Abs(converted-c.expected) > 1e-5*c.expected {
+			t.Errorf("Converting %v %v from %v to %v = %v - expected %v", c.value, c.dimension, c.from, c.to, converted, c.expected)
+		}
+		if back := c.to.Convert(converted, c.dimension, c.from); math.Abs(back-c.value) > 1e-12*c.value {
+			t.Errorf("Converting %v %v from %v to %v and back = %v", c.value, c.dimension, c.from, c.to, back)
+		}
+	}
+}
+
+func TestUnitsSymbol(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		units     Units
+		dimension Dimension
+		expected  string
+	}{
+		{SI, Dimensions.Length, "m"},
+		{SI, Dimensions.Velocity, "m/s"},
+		{SI, Dimensions.Acceleration, "m/s²"},
+		{SI, Dimensions.Energy, "kg·m²/s²"},
+		{SI, Dimension{Time: -1}, "1/s"},
+		{SI, Dimension{}, ""},
+		{Astronomical, Dimensions.AngularMomentum, "M☉·AU²/yr"},
+		{Units{}, Dimensions.Jerk, "px/s³"},
+	}
+
+	for _, c := range cases {
+		if symbol := c.units.Symbol(c.dimension); symbol != c.expected {
+			t.Errorf("Labelling %+v in %v units = %q - expected %q", c.dimension, c.units, symbol, c.expected)
+		}
+	}
+}
+
+func TestUnitsConvertEntities(t *testing.T) {
+	t.Parallel()
+	entities := []*Entity{NewEntity(1, 1, 0, 0, 2*math.Pi, 3, 4), NewEntity(3e-6, -1, 0.5, 0, 0, 0, 0)}
+	converted := Astronomical.ConvertEntities(entities, SI)
+	if converted[0].Mass != 1.98847e30 || converted[0].Position.X != 1.495978707e11 || math.Abs(converted[0].Velocity.Y-29785.2) > 0.1 {
+		t.Errorf("Converting %v to SI units: got %v", entities[0], converted[0])
+	}
+
+	for n, e := range SI.ConvertEntities(converted, Astronomical) {
+		if math.Abs(e.Mass-entities[n].Mass) > 1e-12*entities[n].Mass || e.Position.Distance(entities[n].Position) > 1e-12 ||
+			e.Velocity.Subtract(entities[n].Velocity).Length() > 1e-12 || e.Acceleration.Subtract(entities[n].Acceleration).Length() > 1e-12 {
+			t.Errorf("Converting %v to SI units and back: got %v", entities[n], e)
+		}
+	}
+}
+
+// earthorbit returns the sun and the earth on a circular orbit in astronomical units
+func earthorbit() []*Entity {
+	return []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(3.003e-6, 1, 0, 0, 2*math.Pi*math.Sqrt(1+3.003e-6), 0, 0)}
+}
+
+func TestSimulationUnits(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		integrator Integrator
+		units      Units
+		dt         float64
+	}{
+		{VelocityVerlet{}, Astronomical, 1.0 / 1000},
+		{NewHermite(0.01), Astronomical, 1.0 / 1000},
+		{VelocityVerlet{}, SI, 86400},
+	}
+
+	for _, c := range cases {
+		entities := Astronomical.ConvertEntities(earthorbit(), c.units)
+		s := NewSimulation(entities)
+		s.Integrator = c.integrator
+		s.Units = c.units
+		s.Dt = c.dt
+		start := c.units.ConvertEntity(entities[1], Astronomical)
+
+		// A year later the earth is back where it started
+		s.AdvanceTo(Astronomical.Convert(1, Dimensions.Time, c.units))
+		earth := c.units.ConvertEntity(s.Entities[1], Astronomical)
+		if miss := earth.Position.Distance(start.Position); miss > 1e-3 {
+			t.Errorf("Orbiting for a year with %T in %v units: missed by %v AU", c.integrator, c.units, miss)
+		}
+	}
+}
+
+func TestUnitsDiagnose(t *testing.T) {
+	t.Parallel()
+	entities := earthorbit()
+	diagnostics := Astronomical.Diagnose(entities, Softening{})
+	si := SI.Diagnose(Astronomical.ConvertEntities(entities, SI), Softening{})
+	for _, c := range []struct {
+		name     string
+		value    float64
+		expected float64
+	}{
+		{"kinetic energy", diagnostics.Kinetic, si.Kinetic},
+		{"potential energy", diagnostics.Potential, si.Potential},
+		{"angular momentum", Astronomical.Convert(diagnostics.AngularMomentum, Dimensions.AngularMomentum, SI), si.AngularMomentum},
+	} {
+		if c.name != "angular momentum" {
+			c.value = Astronomical.Convert(c.value, Dimensions.Energy, SI)
+		}
+		if math.Abs(c.value-c.expected) > 1e-3*math.Abs(c.expected) {
+			t.Errorf("Diagnosing an orbit in astronomical units: got %v %v - expected %v", c.name, c.value, c.expected)
+		}
+	}
+
+	// Plain Diagnose is in playground units
+	if Diagnose(entities, Softening{}) != (Units{}).Diagnose(entities, Softening{}) {
+		t.Errorf("Diagnosing in the zero units differs from plain diagnosing")
+	}
+}
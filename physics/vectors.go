@@ -5,90 +5,105 @@ import (
 	"math"
 )
 
-type Vector2D struct {
-	X float64
-	Y float64
+// Float is the constraint satisfied by the floating point types vectors and points may be built on
+type Float interface {
+	~float32 | ~float64
 }
 
+// VectorOf is a two dimensional vector with components of any floating point type, float32 halving the memory of
+// large runs at the cost of precision
+type VectorOf[T Float] struct {
+	X T
+	Y T
+}
+
+// Vector2D is the double precision vector used throughout the simulation
+type Vector2D = VectorOf[float64]
+
 // NewVector2D creates a new Vector2D with supplied x and y components
-func NewVector2D(x float64, y float64) *Vector2D {
-	return &Vector2D{X: x, Y: y}
+func NewVector2D(x float64, y float64) Vector2D {
+	return Vector2D{X: x, Y: y}
+}
+
+// NewVectorOf creates a new VectorOf with supplied x and y components
+func NewVectorOf[T Float](x T, y T) VectorOf[T] {
+	return VectorOf[T]{X: x, Y: y}
 }
 
-// Add returns a new Vector2D of the addition of two vectors.
-func (v1 *Vector2D) Add(v2 *Vector2D) *Vector2D {
-	return NewVector2D(v1.X+v2.X, v1.Y+v2.Y)
+// Add returns a new vector of the addition of two vectors.
+func (v1 VectorOf[T]) Add(v2 VectorOf[T]) VectorOf[T] {
+	return NewVectorOf(v1.X+v2.X, v1.Y+v2.Y)
 }
 
-// Subtract returns a new Vector2D of the subtraction of two vectors.
-func (v1 *Vector2D) Subtract(v2 *Vector2D) *Vector2D {
-	return NewVector2D(v1.X-v2.X, v1.Y-v2.Y)
+// Subtract returns a new vector of the subtraction of two vectors.
+func (v1 VectorOf[T]) Subtract(v2 VectorOf[T]) VectorOf[T] {
+	return NewVectorOf(v1.X-v2.X, v1.Y-v2.Y)
 }
 
-// Scalarmul returns a new Vector2D of the vector components multiplied
+// Scalarmul returns a new vector of the vector components multiplied
 // each by the scalar value.
-func (v *Vector2D) Scalarmul(scalar float64) *Vector2D {
-	return NewVector2D(v.X*scalar, v.Y*scalar)
+func (v VectorOf[T]) Scalarmul(scalar T) VectorOf[T] {
+	return NewVectorOf(v.X*scalar, v.Y*scalar)
 }
 
 // Dotproduct returns the dot product of two vectors.
-func (v1 *Vector2D) Dotproduct(v2 *Vector2D) float64 {
+func (v1 VectorOf[T]) Dotproduct(v2 VectorOf[T]) T {
 	return v1.X*v2.X + v1.Y*v2.Y
 }
 
 // Length returns the scalar length of the vector.
-func (v *Vector2D) Length() float64 {
-	return math.Sqrt(v.X*v.X + v.Y*v.Y)
+func (v VectorOf[T]) Length() T {
+	return T(math.Sqrt(float64(v.X*v.X + v.Y*v.Y)))
 }
 
-// Normalize returns a new Vector2D that is a normalized version
+// Normalize returns a new vector that is a normalized version
 // of the original vector.
-func (v *Vector2D) Normalize() *Vector2D {
+func (v VectorOf[T]) Normalize() VectorOf[T] {
 	normlen := 1.0 / v.Length()
-	return NewVector2D(v.X*normlen, v.Y*normlen)
+	return NewVectorOf(v.X*normlen, v.Y*normlen)
 }
 
-// Rotate returns a new Vector2D that is a counterclockwise
+// Rotate returns a new vector that is a counterclockwise
 // rotation of given radians of the original vector.
-func (v *Vector2D) Rotate(radians float64) *Vector2D {
-	cr := math.Cos(radians)
-	cs := math.Sin(radians)
-	return NewVector2D(v.X*cr-v.Y*cs, v.X*cs+v.Y*cr)
+func (v VectorOf[T]) Rotate(radians float64) VectorOf[T] {
+	cr := T(math.Cos(radians))
+	cs := T(math.Sin(radians))
+	return NewVectorOf(v.X*cr-v.Y*cs, v.X*cs+v.Y*cr)
 }
 
-// InvertX returns a new Vector2D that has an inverted x component
-func (v *Vector2D) InvertX() *Vector2D {
+// InvertX returns a new vector that has an inverted x component
+func (v VectorOf[T]) InvertX() VectorOf[T] {
 	// Do not use the IEEE754 negative zero
 	if v.X == 0 {
-		return NewVector2D(0, v.Y)
+		return NewVectorOf(0, v.Y)
 	}
-	return NewVector2D(-v.X, v.Y)
+	return NewVectorOf(-v.X, v.Y)
 }
 
-// InvertY returns a new Vector2D that has an inverted y component
-func (v *Vector2D) InvertY() *Vector2D {
+// InvertY returns a new vector that has an inverted y component
+func (v VectorOf[T]) InvertY() VectorOf[T] {
 	// Do not use the IEEE754 negative zero
 	if v.Y == 0 {
-		return NewVector2D(v.X, 0)
+		return NewVectorOf(v.X, 0)
 	}
-	return NewVector2D(v.X, -v.Y)
+	return NewVectorOf(v.X, -v.Y)
 }
 
-// Invert returns a new Vector2D that has both x and y component inverted
-func (v *Vector2D) Invert() *Vector2D {
+// Invert returns a new vector that has both x and y component inverted
+func (v VectorOf[T]) Invert() VectorOf[T] {
 	// Do not use the IEEE754 negative zero
 	if v.X == 0 && v.Y == 0 {
-		return NewVector2D(0, 0)
+		return NewVectorOf[T](0, 0)
 	} else if v.X == 0 {
-		return NewVector2D(0, -v.Y)
+		return NewVectorOf(0, -v.Y)
 	} else if v.Y == 0 {
-		return NewVector2D(-v.X, 0)
+		return NewVectorOf(-v.X, 0)
 	} else {
-		return NewVector2D(-v.X, -v.Y)
+		return NewVectorOf(-v.X, -v.Y)
 	}
 }
 
 // String returns the formatted string "Vector{X: ..., ...}".
-func (v *Vector2D) String() string {
+func (v VectorOf[T]) String() string {
 	return fmt.Sprintf("Vector{X: %v, %v}", v.X, v.Y)
 }
@@ -0,0 +1,95 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+)
+
+type Vector3D struct {
+	X float64
+	Y float64
+	Z float64
+}
+
+// NewVector3D creates a new Vector3D with supplied x, y and z components
+func NewVector3D(x float64, y float64, z float64) Vector3D {
+	return Vector3D{X: x, Y: y, Z: z}
+}
+
+// Add returns a new Vector3D of the addition of two vectors.
+func (v1 Vector3D) Add(v2 Vector3D) Vector3D {
+	return NewVector3D(v1.X+v2.X, v1.Y+v2.Y, v1.Z+v2.Z)
+}
+
+// Subtract returns a new Vector3D of the subtraction of two vectors.
+func (v1 Vector3D) Subtract(v2 Vector3D) Vector3D {
+	return NewVector3D(v1.X-v2.X, v1.Y-v2.Y, v1.Z-v2.Z)
+}
+
+// Scalarmul returns a new Vector3D of the vector components multiplied
+// each by the scalar value.
+func (v Vector3D) Scalarmul(scalar float64) Vector3D {
+	return NewVector3D(v.X*scalar, v.Y*scalar, v.Z*scalar)
+}
+
+// Dotproduct returns the dot product of two vectors.
+func (v1 Vector3D) Dotproduct(v2 Vector3D) float64 {
+	return v1.X*v2.X + v1.Y*v2.Y + v1.Z*v2.Z
+}
+
+// Crossproduct returns a new Vector3D perpendicular to both vectors, following
+// the right hand rule, whose length is the area of the parallelogram they span.
+func (v1 Vector3D) Crossproduct(v2 Vector3D) Vector3D {
+	return NewVector3D(v1.Y*v2.Z-v1.Z*v2.Y, v1.Z*v2.X-v1.X*v2.Z, v1.X*v2.Y-v1.Y*v2.X)
+}
+
+// Length returns the scalar length of the vector.
+func (v Vector3D) Length() float64 {
+	return math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+}
+
+// Normalize returns a new Vector3D that is a normalized version
+// of the original vector.
+func (v Vector3D) Normalize() Vector3D {
+	normlen := 1.0 / v.Length()
+	return NewVector3D(v.X*normlen, v.Y*normlen, v.Z*normlen)
+}
+
+// Rotate returns a new Vector3D that is a counterclockwise rotation of given
+// radians of the original vector about the axis, looking down the axis.
+func (v Vector3D) Rotate(axis Vector3D, radians float64) Vector3D {
+	// Rodrigues' rotation formula
+	k := axis.Normalize()
+	cr := math.Cos(radians)
+	cs := math.Sin(radians)
+	return v.Scalarmul(cr).Add(k.Crossproduct(v).Scalarmul(cs)).Add(k.Scalarmul(k.Dotproduct(v) * (1 - cr)))
+}
+
+// RotateX returns a new Vector3D that is a counterclockwise rotation of given
+// radians of the original vector about the x axis.
+func (v Vector3D) RotateX(radians float64) Vector3D {
+	cr := math.Cos(radians)
+	cs := math.Sin(radians)
+	return NewVector3D(v.X, v.Y*cr-v.Z*cs, v.Y*cs+v.Z*cr)
+}
+
+// RotateY returns a new Vector3D that is a counterclockwise rotation of given
+// radians of the original vector about the y axis.
+func (v Vector3D) RotateY(radians float64) Vector3D {
+	cr := math.Cos(radians)
+	cs := math.Sin(radians)
+	return NewVector3D(v.X*cr+v.Z*cs, v.Y, -v.X*cs+v.Z*cr)
+}
+
+// RotateZ returns a new Vector3D that is a counterclockwise rotation of given
+// radians of the original vector about the z axis.
+func (v Vector3D) RotateZ(radians float64) Vector3D {
+	cr := math.Cos(radians)
+	cs := math.Sin(radians)
+	return NewVector3D(v.X*cr-v.Y*cs, v.X*cs+v.Y*cr, v.Z)
+}
+
+// String returns the formatted string "Vector3D{X: ..., ..., ...}".
+func (v Vector3D) String() string {
+	return fmt.Sprintf("Vector3D{X: %v, %v, %v}", v.X, v.Y, v.Z)
+}
@@ -0,0 +1,136 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// vectors3dinequal returns whether the first vector rounded to the given precision differs from the second
+func vectors3dinequal(v1 Vector3D, v2 Vector3D, testprecision int) bool {
+	xinequal := utils.RoundPrecision(v1.X, testprecision) != v2.X
+	yinequal := utils.RoundPrecision(v1.Y, testprecision) != v2.Y
+	zinequal := utils.RoundPrecision(v1.Z, testprecision) != v2.Z
+	return xinequal || yinequal || zinequal
+}
+
+func TestVector3DArithmetic(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		v1         Vector3D
+		v2         Vector3D
+		sum        Vector3D
+		difference Vector3D
+		dotproduct float64
+	}{
+		{NewVector3D(0, 1, 0), NewVector3D(2, 0, 0), NewVector3D(2, 1, 0), NewVector3D(-2, 1, 0), 0},
+		{NewVector3D(5, 3, 1), NewVector3D(2, 4, 6), NewVector3D(7, 7, 7), NewVector3D(3, -1, -5), 28},
+		{NewVector3D(4, 3, -2), NewVector3D(-6, -7, 0.5), NewVector3D(-2, -4, -1.5), NewVector3D(10, 10, -2.5), -46},
+	}
+
+	for _, c := range cases {
+		if sum := c.v1.Add(c.v2); !reflect.DeepEqual(sum, c.sum) {
+			t.Errorf("Computing %v + %v = %v - expected %v", c.v1, c.v2, sum, c.sum)
+		}
+		if difference := c.v1.Subtract(c.v2); !reflect.DeepEqual(difference, c.difference) {
+			t.Errorf("Computing %v - %v = %v - expected %v", c.v1, c.v2, difference, c.difference)
+		}
+		if dp := c.v1.Dotproduct(c.v2); dp != c.dotproduct {
+			t.Errorf("Computing %v . %v = %v - expected %v", c.v1, c.v2, dp, c.dotproduct)
+		}
+	}
+}
+
+func TestVector3DCrossproduct(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		v1       Vector3D
+		v2       Vector3D
+		expected Vector3D
+	}{
+		{NewVector3D(1, 0, 0), NewVector3D(0, 1, 0), NewVector3D(0, 0, 1)},
+		{NewVector3D(0, 1, 0), NewVector3D(1, 0, 0), NewVector3D(0, 0, -1)},
+		{NewVector3D(0, 0, 1), NewVector3D(1, 0, 0), NewVector3D(0, 1, 0)},
+		{NewVector3D(2, 3, 4), NewVector3D(2, 3, 4), NewVector3D(0, 0, 0)},
+		{NewVector3D(1, 2, 3), NewVector3D(4, 5, 6), NewVector3D(-3, 6, -3)},
+	}
+
+	for _, c := range cases {
+		cp := c.v1.Crossproduct(c.v2)
+		if !reflect.DeepEqual(cp, c.expected) {
+			t.Errorf("Computing %v x %v = %v - expected %v", c.v1, c.v2, cp, c.expected)
+		}
+		if cp.Dotproduct(c.v1) != 0 || cp.Dotproduct(c.v2) != 0 {
+			t.Errorf("Computing %v x %v = %v - not perpendicular to both", c.v1, c.v2, cp)
+		}
+	}
+}
+
+func TestVector3DLength(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		v        Vector3D
+		expected float64
+	}{
+		{NewVector3D(0, 0, 1), 1},
+		{NewVector3D(2, 3, 6), 7},
+		{NewVector3D(-1, 4, -8), 9},
+	}
+
+	for _, c := range cases {
+		if l := c.v.Length(); l != c.expected {
+			t.Errorf("Computing len(%v) = %v - expected %v", c.v, l, c.expected)
+		}
+		if normv := c.v.Normalize(); utils.RoundPrecision(normv.Length(), testprecision) != 1 {
+			t.Errorf("Computing norm(%v) = %v - expected unit length", c.v, normv)
+		}
+	}
+}
+
+func TestVector3DRotate(t *testing.T) {
+	t.Parallel()
+	testprecision := 4
+	cases := []struct {
+		v        Vector3D
+		axis     Vector3D
+		radians  float64
+		expected Vector3D
+	}{
+		{NewVector3D(1, 0, 0), NewVector3D(0, 0, 1), math.Pi / 2, NewVector3D(0, 1, 0)},
+		{NewVector3D(0, 1, 0), NewVector3D(1, 0, 0), math.Pi / 2, NewVector3D(0, 0, 1)},
+		{NewVector3D(0, 0, 1), NewVector3D(0, 1, 0), math.Pi / 2, NewVector3D(1, 0, 0)},
+		{NewVector3D(2, 2, 5), NewVector3D(0, 0, 3), math.Pi / 4, NewVector3D(0, 2.8284, 5)},
+		{NewVector3D(1, 0, 0), NewVector3D(1, 1, 1), 2 * math.Pi / 3, NewVector3D(0, 1, 0)},
+		{NewVector3D(0.5, -1.2, 3), NewVector3D(0.5, -1.2, 3), 1, NewVector3D(0.5, -1.2, 3)},
+	}
+
+	for _, c := range cases {
+		rotv := c.v.Rotate(c.axis, c.radians)
+		if vectors3dinequal(rotv, c.expected, testprecision) {
+			t.Errorf("Computing rot(%v, %v, %v) = %v - expected %v", c.v, c.axis, c.radians, rotv, c.expected)
+		}
+	}
+}
+
+func TestVector3DRotateAxes(t *testing.T) {
+	t.Parallel()
+	v := NewVector3D(0.5, -1.2, 3)
+	for _, radians := range []float64{0, 0.3, -math.Pi / 3, math.Pi, 5} {
+		cases := []struct {
+			axis     string
+			rotated  Vector3D
+			expected Vector3D
+		}{
+			{"x", v.RotateX(radians), v.Rotate(NewVector3D(1, 0, 0), radians)},
+			{"y", v.RotateY(radians), v.Rotate(NewVector3D(0, 1, 0), radians)},
+			{"z", v.RotateZ(radians), v.Rotate(NewVector3D(0, 0, 1), radians)},
+		}
+		for _, c := range cases {
+			if c.rotated.Subtract(c.expected).Length() > 1e-12 {
+				t.Errorf("Rotating %v by %v about the %v axis got %v - expected %v", v, radians, c.axis, c.rotated, c.expected)
+			}
+		}
+	}
+}
@@ -7,12 +7,27 @@ import (
 	"testing"
 )
 
+// of returns the vector in the precision of T
+func of[T Float](v Vector2D) VectorOf[T] {
+	return NewVectorOf(T(v.X), T(v.Y))
+}
+
+// closeto returns whether a value is within a few units of its precision of the expected value
+func closeto[T Float](value T, expected float64) bool {
+	return math.Abs(float64(value)-expected) <= 1e-6*math.Max(1, math.Abs(expected))
+}
+
+// vectorsclose returns whether a vector is within a few units of its precision of the expected vector
+func vectorsclose[T Float](v VectorOf[T], expected Vector2D) bool {
+	return closeto(v.X, expected.X) && closeto(v.Y, expected.Y)
+}
+
 func TestVectorAdd(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v1       *Vector2D
-		v2       *Vector2D
-		expected *Vector2D
+		v1       Vector2D
+		v2       Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 1), NewVector2D(2, 0), NewVector2D(2, 1)},
 		{NewVector2D(5, 3), NewVector2D(2, 4), NewVector2D(7, 7)},
@@ -25,15 +40,18 @@ func TestVectorAdd(t *testing.T) {
 		if !reflect.DeepEqual(v3, c.expected) {
 			t.Errorf("Computing %v + %v = %v - expected %v", c.v1, c.v2, v3, c.expected)
 		}
+		if v3 := of[float32](c.v1).Add(of[float32](c.v2)); !vectorsclose(v3, c.expected) {
+			t.Errorf("Computing float32 %v + %v = %v - expected %v", c.v1, c.v2, v3, c.expected)
+		}
 	}
 }
 
 func TestVectorSubtract(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v1       *Vector2D
-		v2       *Vector2D
-		expected *Vector2D
+		v1       Vector2D
+		v2       Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 1), NewVector2D(2, 0), NewVector2D(-2, 1)},
 		{NewVector2D(5, 3), NewVector2D(2, 4), NewVector2D(3, -1)},
@@ -46,15 +64,18 @@ func TestVectorSubtract(t *testing.T) {
 		if !reflect.DeepEqual(v3, c.expected) {
 			t.Errorf("Computing %v - %v = %v - expected %v", c.v1, c.v2, v3, c.expected)
 		}
+		if v3 := of[float32](c.v1).Subtract(of[float32](c.v2)); !vectorsclose(v3, c.expected) {
+			t.Errorf("Computing float32 %v - %v = %v - expected %v", c.v1, c.v2, v3, c.expected)
+		}
 	}
 }
 
 func TestVectorScalarmul(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v1       *Vector2D
+		v1       Vector2D
 		scalar   float64
-		expected *Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 1), 1.0, NewVector2D(0, 1)},
 		{NewVector2D(5, 3), -1.0, NewVector2D(-5, -3)},
@@ -67,14 +88,17 @@ func TestVectorScalarmul(t *testing.T) {
 		if !reflect.DeepEqual(v2, c.expected) {
 			t.Errorf("Computing %v * %v = %v - expected %v", c.v1, c.scalar, v2, c.expected)
 		}
+		if v2 := of[float32](c.v1).Scalarmul(float32(c.scalar)); !vectorsclose(v2, c.expected) {
+			t.Errorf("Computing float32 %v * %v = %v - expected %v", c.v1, c.scalar, v2, c.expected)
+		}
 	}
 }
 
 func TestVectorDotproduct(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v1       *Vector2D
-		v2       *Vector2D
+		v1       Vector2D
+		v2       Vector2D
 		expected float64
 	}{
 		{NewVector2D(0, 1), NewVector2D(2, 0), 0},
@@ -88,13 +112,16 @@ func TestVectorDotproduct(t *testing.T) {
 		if dp != c.expected {
 			t.Errorf("Computing %v . %v = %v - expected %v", c.v1, c.v2, dp, c.expected)
 		}
+		if dp := of[float32](c.v1).Dotproduct(of[float32](c.v2)); !closeto(dp, c.expected) {
+			t.Errorf("Computing float32 %v . %v = %v - expected %v", c.v1, c.v2, dp, c.expected)
+		}
 	}
 }
 
 func TestVectorLength(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v        *Vector2D
+		v        Vector2D
 		expected float64
 	}{
 		{NewVector2D(0, 1), 1},
@@ -108,6 +135,9 @@ func TestVectorLength(t *testing.T) {
 		if l != c.expected {
 			t.Errorf("Computing len(%v) = %v - expected %v", c.v, l, c.expected)
 		}
+		if l := of[float32](c.v).Length(); !closeto(l, c.expected) {
+			t.Errorf("Computing float32 len(%v) = %v - expected %v", c.v, l, c.expected)
+		}
 	}
 }
 
@@ -115,8 +145,8 @@ func TestVectorNormalize(t *testing.T) {
 	t.Parallel()
 	testprecision := 4
 	cases := []struct {
-		v        *Vector2D
-		expected *Vector2D
+		v        Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 1), NewVector2D(0, 1)},
 		{NewVector2D(4, 3), NewVector2D(0.8, 0.6)},
@@ -129,6 +159,10 @@ func TestVectorNormalize(t *testing.T) {
 		if utils.RoundPrecision(normv.X, testprecision) != c.expected.X || utils.RoundPrecision(normv.Y, testprecision) != c.expected.Y {
 			t.Errorf("Computing norm(%v) = %v - expected %v", c.v, normv, c.expected)
 		}
+		normv32 := of[float32](c.v).Normalize()
+		if utils.RoundPrecision(float64(normv32.X), testprecision) != c.expected.X || utils.RoundPrecision(float64(normv32.Y), testprecision) != c.expected.Y {
+			t.Errorf("Computing float32 norm(%v) = %v - expected %v", c.v, normv32, c.expected)
+		}
 	}
 }
 
@@ -136,9 +170,9 @@ func TestVectorRotate(t *testing.T) {
 	t.Parallel()
 	testprecision := 4
 	cases := []struct {
-		v        *Vector2D
+		v        Vector2D
 		radians  float64
-		expected *Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 1), 0, NewVector2D(0, 1)},
 		{NewVector2D(0, 1), math.Pi / 2, NewVector2D(-1, 0)},
@@ -152,13 +186,17 @@ func TestVectorRotate(t *testing.T) {
 		if utils.RoundPrecision(rotv.X, testprecision) != c.expected.X || utils.RoundPrecision(rotv.Y, testprecision) != c.expected.Y {
 			t.Errorf("Computing rot(%v, %v) = %v - expected %v", c.v, c.radians, rotv, c.expected)
 		}
+		rotv32 := of[float32](c.v).Rotate(c.radians)
+		if utils.RoundPrecision(float64(rotv32.X), testprecision) != c.expected.X || utils.RoundPrecision(float64(rotv32.Y), testprecision) != c.expected.Y {
+			t.Errorf("Computing float32 rot(%v, %v) = %v - expected %v", c.v, c.radians, rotv32, c.expected)
+		}
 	}
 }
 func TestVectorInvertX(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v        *Vector2D
-		expected *Vector2D
+		v        Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 0), NewVector2D(0, 0)},
 		{NewVector2D(1, 0), NewVector2D(-1, 0)},
@@ -171,14 +209,17 @@ func TestVectorInvertX(t *testing.T) {
 		if !reflect.DeepEqual(invertedxv, c.expected) {
 			t.Errorf("Computing invertx(%v) = %v - expected %v", c.v, invertedxv, c.expected)
 		}
+		if invertedxv := of[float32](c.v).InvertX(); invertedxv != of[float32](c.expected) {
+			t.Errorf("Computing float32 invertx(%v) = %v - expected %v", c.v, invertedxv, c.expected)
+		}
 	}
 }
 
 func TestVectorInvertY(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v        *Vector2D
-		expected *Vector2D
+		v        Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 0), NewVector2D(0, 0)},
 		{NewVector2D(1, 0), NewVector2D(1, 0)},
@@ -191,14 +232,17 @@ func TestVectorInvertY(t *testing.T) {
 		if !reflect.DeepEqual(invertedyv, c.expected) {
 			t.Errorf("Computing inverty(%v) = %v - expected %v", c.v, invertedyv, c.expected)
 		}
+		if invertedyv := of[float32](c.v).InvertY(); invertedyv != of[float32](c.expected) {
+			t.Errorf("Computing float32 inverty(%v) = %v - expected %v", c.v, invertedyv, c.expected)
+		}
 	}
 }
 
 func TestVectorInvert(t *testing.T) {
 	t.Parallel()
 	cases := []struct {
-		v        *Vector2D
-		expected *Vector2D
+		v        Vector2D
+		expected Vector2D
 	}{
 		{NewVector2D(0, 0), NewVector2D(0, 0)},
 		{NewVector2D(1, 0), NewVector2D(-1, 0)},
@@ -212,5 +256,20 @@ func TestVectorInvert(t *testing.T) {
 		if !reflect.DeepEqual(invertedv, c.expected) {
 			t.Errorf("Computing invert(%v) = %v - expected %v", c.v, invertedv, c.expected)
 		}
+		if invertedv := of[float32](c.v).Invert(); invertedv != of[float32](c.expected) {
+			t.Errorf("Computing float32 invert(%v) = %v - expected %v", c.v, invertedv, c.expected)
+		}
+	}
+}
+
+func BenchmarkVectorArithmetic(b *testing.B) {
+	b.ReportAllocs()
+	v1, v2 := NewVector2D(1.25, -3.5), NewVector2D(0.5, 2)
+	sum := NewVector2D(0, 0)
+	for i := 0; i < b.N; i++ {
+		sum = sum.Add(v1.Subtract(v2).Scalarmul(0.5)).Rotate(0.01)
+	}
+	if math.IsNaN(sum.Length()) {
+		b.Fatal("Vector arithmetic produced NaN")
 	}
 }
@@ -0,0 +1,224 @@
+package physics
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// Policy decides what a Watchdog does once it finds a step gone bad
+type Policy int
+
+const (
+	// LogAlarm logs the first bad step and lets the simulation carry on
+	LogAlarm Policy = iota
+	// PauseOnAlarm stops advancing the simulation at every new alarm, which the caller can resume from. Entities
+	// that stay non-finite step after step only pause it once
+	PauseOnAlarm
+	// HaltOnAlarm stops advancing the simulation at the first bad step and refuses to advance it again until
+	// the watchdog is reset
+	HaltOnAlarm
+)
+
+// String returns the name of the policy
+func (p Policy) String() string {
+	switch p {
+	case LogAlarm:
+		return "Log"
+	case PauseOnAlarm:
+		return "Pause"
+	case HaltOnAlarm:
+		return "Halt"
+	default:
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+}
+
+// Alarm describes a bad step found by a Watchdog: either the first entity with a field that is not a finite
+// number, or an energy drift beyond the watchdog's limit
+type Alarm struct {
+	Step   int
+	Time   float64
+	Entity int
+	Field  string
+	Drift  float64
+}
+
+// Error returns a description of what went bad and when
+func (a *Alarm) Error() string {
+	if a.Field != "" {
+		return fmt.Sprintf("step %v at %vs: entity %v has a non-finite %v", a.Step, a.Time, a.Entity, a.Field)
+	}
+	return fmt.Sprintf("step %v at %vs: energy drifted by %.3g", a.Step, a.Time, a.Drift)
+}
+
+// Watchdog checks entities after every step of a simulation for fields that are NaN or infinite and for total
+// energy, softened by Softening and measured in Units, drifting from the first step checked by more than MaxDrift
+// relative to where it started. A Simulation sets Units to its own before every check, so they only need setting
+// for a watchdog checking entities directly. A zero MaxDrift only checks for non-finite fields. Energy gained or lost on purpose
+// by boundaries and colliders is not counted as drift, and after a drift alarm the drift is measured from the step
+// that raised it. Compensated sums the energy with compensated summation, as DiagnoseCompensated does. Policy
+// decides what happens on a bad step, and First holds the first bad step found while Alarms counts them all
+type Watchdog struct {
+	MaxDrift    float64
+	Softening   Softening
+	Units       Units
+	Compensated bool
+	Policy      Policy
+	First       *Alarm
+	Alarms      int
+
+	steps      int
+	reference  Diagnostics
+	referenced bool
+	last       *Alarm
+}
+
+// Check checks the entities after a step ending at the given simulated time, returning an error if the policy
+// means the simulation should stop
+func (w *Watchdog) Check(entities []*Entity, time float64) error {
+	if w.Policy == HaltOnAlarm && w.First != nil {
+		return w.First
+	}
+	w.steps++
+
+	alarm := w.inspect(entities, time)
+	if alarm == nil {
+		w.last = nil
+		return nil
+	}
+	w.Alarms++
+
+	// Non-finite numbers spread but never heal, so a blow up is only new on the first step it shows up
+	fresh := alarm.Field == "" || w.last == nil || w.last.Field == ""
+	w.last = alarm
+	if w.First == nil {
+		w.First = alarm
+		if w.Policy == LogAlarm {
+			log.Printf("Watchdog: %v", alarm)
+		}
+	}
+
+	switch w.Policy {
+	case PauseOnAlarm:
+		if !fresh {
+			return nil
+		}
+		return alarm
+	case HaltOnAlarm:
+		return w.First
+	default:
+		return nil
+	}
+}
+
+// Halted returns the alarm a halting watchdog stopped on, or nil if the simulation may advance
+func (w *Watchdog) Halted() error {
+	if w.Policy == HaltOnAlarm && w.First != nil {
+		return w.First
+	}
+	return nil
+}
+
+// Reset forgets every alarm and the reference energy, so the next step checked starts afresh
+func (w *Watchdog) Reset() {
+	w.First = nil
+	w.Alarms = 0
+	w.steps = 0
+	w.referenced = false
+	w.last = nil
+}
+
+// Intervened tells the watchdog that the boundary or collider changed the entities on purpose after a step, given
+// copies of them from before, so the energy that gained or lost is not counted as drift
+func (w *Watchdog) Intervened(before []*Entity, after []*Entity) {
+	if w.MaxDrift <= 0 || !w.referenced || unchanged(before, after) {
+		return
+	}
+	from, to := w.diagnose(before), w.diagnose(after)
+	kinetic, potential := to.Kinetic-from.Kinetic, to.Potential-from.Potential
+	if math.IsNaN(kinetic+potential) || math.IsInf(kinetic+potential, 0) {
+		return
+	}
+	w.reference.Kinetic += kinetic
+	w.reference.Potential += potential
+}
+
+// inspect returns an alarm for the first entity with a non-finite field, or for energy drifting too far, or nil
+func (w *Watchdog) inspect(entities []*Entity, time float64) *Alarm {
+	for n, e := range entities {
+		if field := nonfinitefield(e); field != "" {
+			return &Alarm{Step: w.steps, Time: time, Entity: n, Field: field}
+		}
+	}
+
+	if w.MaxDrift <= 0 {
+		return nil
+	}
+	diagnostics := w.diagnose(entities)
+	if !w.referenced {
+		w.reference, w.referenced = diagnostics, true
+		return nil
+	}
+	if drift := diagnostics.Drift(w.reference).Energy; drift > w.MaxDrift {
+		w.reference = diagnostics
+		return &Alarm{Step: w.steps, Time: time, Entity: -1, Drift: drift}
+	}
+	return nil
+}
+
+// diagnose returns the diagnostics of the entities in the watchdog's units, compensated if it is
+func (w *Watchdog) diagnose(entities []*Entity) Diagnostics {
+	if w.Compensated {
+		return w.Units.DiagnoseCompensated(entities, w.Softening)
+	}
+	return w.Units.Diagnose(entities, w.Softening)
+}
+
+// snapshot returns copies of the entities, for telling a watchdog what they were before they were changed
+func snapshot(entities []*Entity) []*Entity {
+	copies := make([]*Entity, len(entities))
+	for n, e := range entities {
+		copied := *e
+		copies[n] = &copied
+	}
+	return copies
+}
+
+// unchanged returns whether the entities hold the same values as the earlier copies of them
+func unchanged(before []*Entity, after []*Entity) bool {
+	if len(before) != len(after) {
+		return false
+	}
+	for n := range before {
+		if *before[n] != *after[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// nonfinitefield returns the name of the first field of the entity that is NaN or infinite, or an empty string
+func nonfinitefield(e *Entity) string {
+	fields := []struct {
+		name  string
+		value float64
+	}{
+		{"mass", e.Mass},
+		{"radius", e.Radius},
+		{"x position", e.Position.X},
+		{"y position", e.Position.Y},
+		{"x velocity", e.Velocity.X},
+		{"y velocity", e.Velocity.Y},
+		{"x acceleration", e.Acceleration.X},
+		{"y acceleration", e.Acceleration.Y},
+		{"x jerk", e.Jerk.X},
+		{"y jerk", e.Jerk.Y},
+	}
+	for _, field := range fields {
+		if math.IsNaN(field.value) || math.IsInf(field.value, 0) {
+			return field.name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,164 @@
+package physics
+
+import (
+	"github.com/tkajder/gravitysimulator/utils"
+	"math"
+	"testing"
+)
+
+func TestWatchdogNonFinite(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		poison func(e *Entity)
+		field  string
+	}{
+		{func(e *Entity) { e.Mass = math.NaN() }, "mass"},
+		{func(e *Entity) { e.Position.Y = math.Inf(1) }, "y position"},
+		{func(e *Entity) { e.Velocity.X = math.Inf(-1) }, "x velocity"},
+		{func(e *Entity) { e.Jerk.Y = math.NaN() }, "y jerk"},
+	}
+
+	for _, c := range cases {
+		entities := []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1, 1, 0, 0, 0, 0, 0), NewEntity(1, 2, 0, 0, 0, 0, 0)}
+		watchdog := &Watchdog{Policy: PauseOnAlarm}
+		if err := watchdog.Check(entities, 0.1); err != nil {
+			t.Errorf("Checking healthy entities: got %v - expected no alarm", err)
+		}
+		c.poison(entities[1])
+		c.poison(entities[2])
+		err := watchdog.Check(entities, 0.2)
+		if alarm, ok := err.(*Alarm); !ok || alarm.Step != 2 || alarm.Time != 0.2 || alarm.Entity != 1 || alarm.Field != c.field {
+			t.Errorf("Checking poisoned entities: got %v - expected entity 1 with a non-finite %v at step 2", err, c.field)
+		}
+	}
+}
+
+func TestWatchdogDrift(t *testing.T) {
+	t.Parallel()
+	entities, _ := circularorbit(100)
+	watchdog := &Watchdog{MaxDrift: 1e-3, Policy: PauseOnAlarm}
+	for i := 0; i < 10; i++ {
+		if err := watchdog.Check(entities, float64(i)); err != nil {
+			t.Fatalf("Checking unchanged entities: got %v - expected no alarm", err)
+		}
+	}
+
+	entities[1].Velocity = entities[1].Velocity.Scalarmul(1.01)
+	if err := watchdog.Check(entities, 10); err == nil || watchdog.First.Entity != -1 || watchdog.First.Step != 11 || watchdog.First.Drift < 1e-3 {
+		t.Errorf("Checking entities that gained energy: got %v - expected an energy drift alarm at step 11", err)
+	}
+}
+
+func TestWatchdogIntervened(t *testing.T) {
+	t.Parallel()
+
+	// Energy lost to damped walls and merges is not drift
+	entities := []*Entity{NewEntity(1, -50, 0, 20, 10, 0, 0), NewEntity(1, 50, 0, -20, 0, 0, 0), NewEntity(1, 0, 60, 0, 100, 0, 0)}
+	for _, e := range entities {
+		e.Radius = 10
+	}
+	simulation := NewSimulation(entities)
+	simulation.Boundary = ReflectiveBoundary{Domain: NewDomain(200, 200), Restitution: 0.7}
+	simulation.Collider = &Merger{}
+	simulation.Watchdog = &Watchdog{MaxDrift: 1e-3, Policy: PauseOnAlarm}
+	if err := simulation.AdvanceTo(5); err != nil || len(simulation.Entities) == 3 {
+		t.Errorf("Simulating damped bounces and a merge: got %v with %v - expected no alarm after a merge", err, simulation.Entities)
+	}
+
+	// But a drift beyond the limit still pauses the simulation once, and again only after drifting that far anew
+	watchdog := &Watchdog{MaxDrift: 1e-3, Policy: PauseOnAlarm}
+	entities, _ = circularorbit(100)
+	watchdog.Check(entities, 0)
+	entities[1].Velocity = entities[1].Velocity.Scalarmul(1.01)
+	if err := watchdog.Check(entities, 1); err == nil {
+		t.Errorf("Checking entities that gained energy: got no alarm - expected a drift alarm")
+	}
+	if err := watchdog.Check(entities, 2); err != nil {
+		t.Errorf("Checking entities that gained no more energy: got %v - expected no alarm", err)
+	}
+}
+
+func TestWatchdogCompensated(t *testing.T) {
+	t.Parallel()
+
+	// The reference of a compensated watchdog keeps the planets a plain one rounds away next to a heavy star
+	entities := []*Entity{NewEntity(1e16, 0, 0, 1, 0, 0, 0)}
+	for i := 0; i < 1000; i++ {
+		entities = append(entities, NewEntity(0.5, 100, float64(i), 1, 0, 0, 0))
+	}
+	cases := []struct {
+		compensated bool
+		mass        float64
+	}{
+		{false, 1e16},
+		{true, 1e16 + 500},
+	}
+	for _, c := range cases {
+		watchdog := &Watchdog{MaxDrift: 1e-3, Compensated: c.compensated}
+		watchdog.Check(entities, 0)
+		if watchdog.reference.Mass != c.mass {
+			t.Errorf("Checking a heavy star with compensation %v: got reference mass %v - expected %v", c.compensated, watchdog.reference.Mass, c.mass)
+		}
+	}
+}
+
+func TestSimulationWatchdog(t *testing.T) {
+	t.Parallel()
+
+	// A pair whose velocities are poisoned after a second of healthy steps
+	blowup := func(policy Policy) *Simulation {
+		entities, _ := circularorbit(100)
+		simulation := NewSimulation(entities)
+		simulation.Watchdog = &Watchdog{MaxDrift: 1e-3, Policy: policy}
+		if err := simulation.AdvanceTo(1); err != nil {
+			t.Fatalf("Advancing a healthy orbit with the %v policy: got %v - expected no alarm", policy, err)
+		}
+		entities[1].Velocity = NewVector2D(math.NaN(), 0)
+		return simulation
+	}
+
+	// Logging lets the simulation carry on regardless
+	simulation := blowup(LogAlarm)
+	if err := simulation.AdvanceTo(2); err != nil || simulation.Time != 2 || simulation.Watchdog.First == nil || simulation.Watchdog.First.Step != 101 {
+		t.Errorf("Advancing a blown up orbit with the log policy: got %v at %v - expected to reach 2 with the first alarm at step 101", err, simulation.Time)
+	}
+
+	// Pausing stops at the bad step but can carry on afterwards
+	simulation = blowup(PauseOnAlarm)
+	if err := simulation.AdvanceTo(2); err == nil || utils.RoundPrecision(simulation.Time, 6) != 1.01 {
+		t.Errorf("Advancing a blown up orbit with the pause policy: got %v at %v - expected an alarm at 1.01", err, simulation.Time)
+	}
+	if err := simulation.AdvanceTo(1.05); err != nil || simulation.Time != 1.05 || simulation.Watchdog.First.Step != 101 || simulation.Watchdog.Alarms != 5 {
+		t.Errorf("Resuming a blown up orbit with the pause policy: got %v at %v after %v alarms - expected to reach 1.05 without pausing for the same blow up", err, simulation.Time, simulation.Watchdog.Alarms)
+	}
+
+	// Halting refuses to advance again until reset
+	simulation = blowup(HaltOnAlarm)
+	simulation.AdvanceTo(2)
+	time := simulation.Time
+	if err := simulation.AdvanceTo(3); err == nil || simulation.Time != time {
+		t.Errorf("Resuming a halted orbit: got %v at %v - expected the alarm at %v", err, simulation.Time, time)
+	}
+	if err := simulation.Tick(); err == nil || simulation.Time != time {
+		t.Errorf("Ticking a halted orbit: got %v at %v - expected the alarm at %v", err, simulation.Time, time)
+	}
+	simulation.Watchdog.Reset()
+	simulation.Entities, _ = circularorbit(100)
+	if err := simulation.AdvanceTo(3); err != nil || simulation.Time != 3 {
+		t.Errorf("Advancing a reset orbit: got %v at %v - expected to reach 3", err, simulation.Time)
+	}
+}
+
+func TestSimulationWatchdogUnits(t *testing.T) {
+	t.Parallel()
+
+	// An eccentric orbit at 1 AU conserves energy measured with the gravity of the simulation's units
+	entities := []*Entity{NewEntity(1, 0, 0, 0, 0, 0, 0), NewEntity(1e-6, 1, 0, 0, 2*math.Pi*math.Sqrt(1.5), 0, 0)}
+	simulation := NewSimulation(entities)
+	simulation.Units = Astronomical
+	simulation.Dt = 0.001
+	simulation.Watchdog = &Watchdog{MaxDrift: 0.01, Policy: HaltOnAlarm}
+	if err := simulation.AdvanceTo(3); err != nil {
+		t.Errorf("Advancing an eccentric orbit in astronomical units: got %v - expected no alarm", err)
+	}
+}